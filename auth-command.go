@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"tui/auth"
+	"tui/config"
+)
+
+const authUsage = `usage:
+  tui [--profile name] auth login  login with oauth and store token
+  tui auth encrypt-token <path>    write api token into encrypted file`
+
+// runAuthCommand handle `tui auth` subcommands
+func runAuthCommand(profile string, args []string) {
+	switch {
+	case len(args) == 1 && args[0] == "login":
+		runAuthLogin(profile)
+	case len(args) == 2 && args[0] == "encrypt-token":
+		runEncryptToken(args[1])
+	default:
+		fmt.Fprintln(os.Stderr, authUsage)
+		os.Exit(2)
+	}
+}
+
+// runAuthLogin run oauth login in terminal and store token for the tui
+func runAuthLogin(profile string) {
+	cfg := config.NewConfig(profile, config.PromptPassphrase)
+	if cfg.GetAuthMode() != config.AuthModeOAuth {
+		log.Fatalf("auth login: set ATLASSIAN_AUTH=oauth and ATLASSIAN_OAUTH_CLIENT_ID first")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	oauthConfig := cfg.GetOAuthConfig()
+	client := &http.Client{Timeout: cfg.GetRequestTimeout()}
+
+	token, err := auth.Login(ctx, oauthConfig, client, func(authURL string) error {
+		fmt.Printf("Open this url in your browser to login:\n\n%s\n\nWaiting for callback on %s ...\n", authURL, oauthConfig.RedirectURL)
+		return nil
+	})
+	if err != nil {
+		log.Fatalf("auth login: %v", err)
+	}
+
+	resources, err := auth.AccessibleResources(ctx, oauthConfig, client, token.AccessToken)
+	if err != nil {
+		log.Fatalf("auth login: %v", err)
+	}
+
+	// api calls go through cloud id of the configured site
+	siteURL := strings.TrimSuffix(cfg.GetAtlassianURL(), "/")
+	var site *auth.Resource
+	for i, resource := range resources {
+		if strings.TrimSuffix(resource.URL, "/") == siteURL {
+			site = &resources[i]
+			break
+		}
+	}
+	if site == nil {
+		log.Fatalf("auth login: token has no access to %s", siteURL)
+	}
+	token.CloudID = site.ID
+
+	if err := auth.NewFileStore(cfg.GetOAuthTokenPath()).Save(token); err != nil {
+		log.Fatalf("auth login: %v", err)
+	}
+
+	fmt.Printf("Logged in to %s\n", site.URL)
+}
+
+// runEncryptToken prompt for api token and passphrase, then write token file
+// read by ATLASSIAN_TOKEN_SOURCE=file
+func runEncryptToken(path string) {
+	token, err := config.ReadSecret("API token: ")
+	if err != nil {
+		log.Fatalf("auth encrypt-token: %v", err)
+	}
+
+	passphrase, err := config.ReadSecret("Passphrase: ")
+	if err != nil {
+		log.Fatalf("auth encrypt-token: %v", err)
+	}
+
+	confirm, err := config.ReadSecret("Confirm passphrase: ")
+	if err != nil {
+		log.Fatalf("auth encrypt-token: %v", err)
+	}
+
+	if token == "" || passphrase == "" || passphrase != confirm {
+		log.Fatalf("auth encrypt-token: token is empty or passphrases do not match")
+	}
+
+	data, err := config.EncryptToken(token, passphrase)
+	if err != nil {
+		log.Fatalf("auth encrypt-token: %v", err)
+	}
+
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		log.Fatalf("auth encrypt-token: %v", err)
+	}
+
+	fmt.Printf("Token written to %s, set ATLASSIAN_TOKEN_SOURCE=file and ATLASSIAN_TOKEN_FILE=%s\n", path, path)
+}
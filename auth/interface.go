@@ -0,0 +1,15 @@
+package auth
+
+import "context"
+
+type TokenStoreType interface {
+	Load() (*Token, error)
+	Save(*Token) error
+}
+
+type TokenSourceType interface {
+	Token(context.Context) (*Token, error)
+	Invalidate()
+	CloudID() string
+	refresh(context.Context, *Token) (*Token, error)
+}
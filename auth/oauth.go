@@ -0,0 +1,302 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// atlassian 3LO endpoints, api calls go through AtlassianAPIURL/{cloudId}
+const (
+	AtlassianAuthURL      = "https://auth.atlassian.com/authorize"
+	AtlassianTokenURL     = "https://auth.atlassian.com/oauth/token"
+	AtlassianResourcesURL = "https://api.atlassian.com/oauth/token/accessible-resources"
+	AtlassianAPIURL       = "https://api.atlassian.com/ex/jira"
+)
+
+// offline_access is needed to get refresh token
+var DefaultScopes = []string{"read:jira-work", "read:jira-user", "offline_access"}
+
+var ErrNotLoggedIn = errors.New("not logged in, run `tui auth login`")
+
+type OAuthConfig struct {
+	ClientID     string
+	ClientSecret string
+	AuthURL      string
+	TokenURL     string
+	ResourcesURL string
+	RedirectURL  string // must be http://127.0.0.1:<port>/<path>
+	Scopes       []string
+}
+
+type Token struct {
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token"`
+	TokenType    string    `json:"token_type"`
+	ExpiresIn    int       `json:"expires_in,omitempty"`
+	Expiry       time.Time `json:"expiry"`
+	CloudID      string    `json:"cloud_id,omitempty"`
+}
+
+type Resource struct {
+	ID   string `json:"id"`
+	URL  string `json:"url"`
+	Name string `json:"name"`
+}
+
+type tokenRequest struct {
+	GrantType    string `json:"grant_type"`
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret,omitempty"`
+	Code         string `json:"code,omitempty"`
+	RedirectURI  string `json:"redirect_uri,omitempty"`
+	CodeVerifier string `json:"code_verifier,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+}
+
+type tokenError struct {
+	Error       string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+// NewPKCE return random code verifier and its S256 challenge
+func NewPKCE() (string, string, error) {
+	verifier, err := randomString(32)
+	if err != nil {
+		return "", "", err
+	}
+
+	sum := sha256.Sum256([]byte(verifier))
+	return verifier, base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
+
+// AuthCodeURL build authorize url the user has to open in browser
+func AuthCodeURL(config OAuthConfig, state string, challenge string) string {
+	params := url.Values{}
+	params.Set("audience", "api.atlassian.com")
+	params.Set("client_id", config.ClientID)
+	params.Set("scope", strings.Join(config.Scopes, " "))
+	params.Set("redirect_uri", config.RedirectURL)
+	params.Set("state", state)
+	params.Set("response_type", "code")
+	params.Set("prompt", "consent")
+	params.Set("code_challenge", challenge)
+	params.Set("code_challenge_method", "S256")
+
+	return fmt.Sprintf("%s?%s", config.AuthURL, params.Encode())
+}
+
+// Login run authorization code grant with PKCE. It listen on RedirectURL,
+// hand the authorize url to open and exchange code received by callback
+func Login(
+	ctx context.Context,
+	config OAuthConfig,
+	client *http.Client,
+	open func(string) error,
+) (*Token, error) {
+	redirect, err := url.Parse(config.RedirectURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redirect url: %w", err)
+	}
+
+	verifier, challenge, err := NewPKCE()
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := randomString(16)
+	if err != nil {
+		return nil, err
+	}
+
+	listener, err := net.Listen("tcp", redirect.Host)
+	if err != nil {
+		return nil, fmt.Errorf("listen callback: %w", err)
+	}
+
+	codeChan := make(chan string, 1)
+	errChan := make(chan error, 1)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(redirect.Path, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+
+		// callback without our state doesn't answer this login, anyone can
+		// hit the port so keep waiting for the real one
+		if query.Get("state") != state {
+			http.Error(w, "Unknown login request.", http.StatusBadRequest)
+			return
+		}
+
+		// only first result is read, later callbacks mustn't block
+		if query.Get("error") != "" {
+			select {
+			case errChan <- fmt.Errorf("authorization denied: %s", query.Get("error_description")):
+			default:
+			}
+			http.Error(w, "Login failed, check your terminal.", http.StatusBadRequest)
+			return
+		}
+
+		select {
+		case codeChan <- query.Get("code"):
+		default:
+		}
+		fmt.Fprintln(w, "Login complete, you can close this tab.")
+	})
+
+	server := &http.Server{Handler: mux}
+	go server.Serve(listener)
+	defer server.Close()
+
+	if err := open(AuthCodeURL(config, state, challenge)); err != nil {
+		return nil, err
+	}
+
+	var code string
+	select {
+	case code = <-codeChan:
+	case err := <-errChan:
+		return nil, err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	return requestToken(ctx, config, client, tokenRequest{
+		GrantType:    "authorization_code",
+		ClientID:     config.ClientID,
+		ClientSecret: config.ClientSecret,
+		Code:         code,
+		RedirectURI:  config.RedirectURL,
+		CodeVerifier: verifier,
+	})
+}
+
+// Refresh exchange refresh token for new token, atlassian rotate refresh
+// tokens so the returned one must be stored
+func Refresh(
+	ctx context.Context,
+	config OAuthConfig,
+	client *http.Client,
+	refreshToken string,
+) (*Token, error) {
+	token, err := requestToken(ctx, config, client, tokenRequest{
+		GrantType:    "refresh_token",
+		ClientID:     config.ClientID,
+		ClientSecret: config.ClientSecret,
+		RefreshToken: refreshToken,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if token.RefreshToken == "" {
+		token.RefreshToken = refreshToken
+	}
+
+	return token, nil
+}
+
+// AccessibleResources list jira sites the token is granted for
+func AccessibleResources(
+	ctx context.Context,
+	config OAuthConfig,
+	client *http.Client,
+	accessToken string,
+) ([]Resource, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, config.ResourcesURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", accessToken))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("accessible resources: unexpected status %d", resp.StatusCode)
+	}
+
+	resources := []Resource{}
+	if err := json.NewDecoder(resp.Body).Decode(&resources); err != nil {
+		return nil, err
+	}
+
+	return resources, nil
+}
+
+func requestToken(
+	ctx context.Context,
+	config OAuthConfig,
+	client *http.Client,
+	payload tokenRequest,
+) (*Token, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, config.TokenURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	resBody, err := io.ReadAll(io.LimitReader(resp.Body, 1<<16))
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var tokenErr tokenError
+		if json.Unmarshal(resBody, &tokenErr) == nil && tokenErr.Error != "" {
+			return nil, fmt.Errorf("%s: %s: %s", payload.GrantType, tokenErr.Error, tokenErr.Description)
+		}
+		return nil, fmt.Errorf("%s: unexpected status %d", payload.GrantType, resp.StatusCode)
+	}
+
+	var token Token
+	if err := json.Unmarshal(resBody, &token); err != nil {
+		return nil, err
+	}
+	if token.AccessToken == "" {
+		return nil, fmt.Errorf("%s: response has no access token", payload.GrantType)
+	}
+
+	if token.ExpiresIn > 0 {
+		token.Expiry = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	}
+
+	return &token, nil
+}
+
+func randomString(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
@@ -0,0 +1,267 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAuthServer act as authorization server, authorize redirect straight
+// back with code while token endpoint verify PKCE and rotate refresh token
+func fakeAuthServer(t *testing.T, refreshes *int32) *httptest.Server {
+	t.Helper()
+
+	challenges := map[string]string{}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/authorize", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		require.Equal(t, "code", query.Get("response_type"))
+		require.Equal(t, "S256", query.Get("code_challenge_method"))
+
+		challenges["code-1"] = query.Get("code_challenge")
+
+		redirect := fmt.Sprintf("%s?code=code-1&state=%s", query.Get("redirect_uri"), url.QueryEscape(query.Get("state")))
+		http.Redirect(w, r, redirect, http.StatusFound)
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		var body tokenRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		require.Equal(t, "client", body.ClientID)
+
+		switch body.GrantType {
+		case "authorization_code":
+			sum := sha256.Sum256([]byte(body.CodeVerifier))
+			if challenges[body.Code] != base64.RawURLEncoding.EncodeToString(sum[:]) {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"error":"invalid_grant","error_description":"pkce mismatch"}`))
+				return
+			}
+
+			w.Write([]byte(`{"access_token":"access-0","refresh_token":"refresh-0","token_type":"Bearer","expires_in":3600}`))
+		case "refresh_token":
+			n := atomic.AddInt32(refreshes, 1)
+			if body.RefreshToken != fmt.Sprintf("refresh-%d", n-1) {
+				w.WriteHeader(http.StatusForbidden)
+				w.Write([]byte(`{"error":"invalid_grant","error_description":"unknown refresh token"}`))
+				return
+			}
+
+			fmt.Fprintf(w, `{"access_token":"access-%d","refresh_token":"refresh-%d","expires_in":3600}`, n, n)
+		}
+	})
+
+	return httptest.NewServer(mux)
+}
+
+func freeRedirectURL(t *testing.T) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	return fmt.Sprintf("http://%s/callback", listener.Addr().String())
+}
+
+func TestLogin(t *testing.T) {
+	tcs := []struct {
+		name string
+		test func(t *testing.T)
+	}{
+		{
+			name: "exchange code with verifier",
+			test: func(t *testing.T) {
+				var refreshes int32
+				srv := fakeAuthServer(t, &refreshes)
+				defer srv.Close()
+
+				config := OAuthConfig{
+					ClientID:    "client",
+					AuthURL:     srv.URL + "/authorize",
+					TokenURL:    srv.URL + "/token",
+					RedirectURL: freeRedirectURL(t),
+					Scopes:      DefaultScopes,
+				}
+
+				// browser is simulated by following authorize redirect
+				token, err := Login(context.Background(), config, srv.Client(), func(authURL string) error {
+					go func() {
+						resp, err := http.Get(authURL)
+						if err == nil {
+							resp.Body.Close()
+						}
+					}()
+					return nil
+				})
+				require.NoError(t, err)
+				require.Equal(t, "access-0", token.AccessToken)
+				require.Equal(t, "refresh-0", token.RefreshToken)
+				require.WithinDuration(t, time.Now().Add(time.Hour), token.Expiry, time.Minute)
+			},
+		},
+		{
+			name: "ignore callback with other state",
+			test: func(t *testing.T) {
+				var refreshes int32
+				srv := fakeAuthServer(t, &refreshes)
+				defer srv.Close()
+
+				config := OAuthConfig{
+					ClientID:    "client",
+					AuthURL:     srv.URL + "/authorize",
+					TokenURL:    srv.URL + "/token",
+					RedirectURL: freeRedirectURL(t),
+					Scopes:      DefaultScopes,
+				}
+
+				token, err := Login(context.Background(), config, srv.Client(), func(authURL string) error {
+					go func() {
+						for _, query := range []string{"?code=code-1&state=forged", "?error=access_denied&state=forged"} {
+							resp, err := http.Get(config.RedirectURL + query)
+							if err != nil {
+								t.Errorf("forged callback: %v", err)
+								return
+							}
+							resp.Body.Close()
+							if resp.StatusCode != http.StatusBadRequest {
+								t.Errorf("forged callback status %d", resp.StatusCode)
+							}
+						}
+
+						resp, err := http.Get(authURL)
+						if err == nil {
+							resp.Body.Close()
+						}
+					}()
+					return nil
+				})
+				require.NoError(t, err)
+				require.Equal(t, "access-0", token.AccessToken)
+			},
+		},
+		{
+			name: "first denial end login",
+			test: func(t *testing.T) {
+				config := OAuthConfig{
+					ClientID:    "client",
+					AuthURL:     "http://127.0.0.1/authorize",
+					RedirectURL: freeRedirectURL(t),
+				}
+
+				_, err := Login(context.Background(), config, http.DefaultClient, func(authURL string) error {
+					parsed, err := url.Parse(authURL)
+					if err != nil {
+						return err
+					}
+					state := parsed.Query().Get("state")
+
+					// second callback must not block its handler
+					go func() {
+						for i := 0; i < 2; i++ {
+							resp, err := http.Get(config.RedirectURL + "?error=access_denied&error_description=denied&state=" + state)
+							if err == nil {
+								resp.Body.Close()
+							}
+						}
+					}()
+					return nil
+				})
+				require.ErrorContains(t, err, "authorization denied: denied")
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.test(t)
+		})
+	}
+}
+
+func TestTokenSource(t *testing.T) {
+	now := time.Date(2024, 3, 10, 8, 0, 0, 0, time.UTC)
+
+	tcs := []struct {
+		name string
+		test func(t *testing.T)
+	}{
+		{
+			name: "return stored token while valid",
+			test: func(t *testing.T) {
+				var refreshes int32
+				srv := fakeAuthServer(t, &refreshes)
+				defer srv.Close()
+
+				store := NewFileStore(filepath.Join(t.TempDir(), "token.json"))
+				require.NoError(t, store.Save(&Token{AccessToken: "access-0", RefreshToken: "refresh-0", Expiry: now.Add(time.Hour), CloudID: "cloud"}))
+
+				source := NewTokenSource(OAuthConfig{ClientID: "client", TokenURL: srv.URL + "/token"}, srv.Client(), store).(*TokenSource)
+				source.now = func() time.Time { return now }
+
+				token, err := source.Token(context.Background())
+				require.NoError(t, err)
+				require.Equal(t, "access-0", token.AccessToken)
+				require.Equal(t, "cloud", source.CloudID())
+				require.Equal(t, int32(0), atomic.LoadInt32(&refreshes))
+			},
+		},
+		{
+			name: "refresh and persist expired token",
+			test: func(t *testing.T) {
+				var refreshes int32
+				srv := fakeAuthServer(t, &refreshes)
+				defer srv.Close()
+
+				store := NewFileStore(filepath.Join(t.TempDir(), "token.json"))
+				require.NoError(t, store.Save(&Token{AccessToken: "access-0", RefreshToken: "refresh-0", Expiry: now.Add(30 * time.Second), CloudID: "cloud"}))
+
+				source := NewTokenSource(OAuthConfig{ClientID: "client", TokenURL: srv.URL + "/token"}, srv.Client(), store).(*TokenSource)
+				source.now = func() time.Time { return now }
+
+				token, err := source.Token(context.Background())
+				require.NoError(t, err)
+				require.Equal(t, "access-1", token.AccessToken)
+
+				// invalidated token refresh with the rotated refresh token
+				source.Invalidate()
+				token, err = source.Token(context.Background())
+				require.NoError(t, err)
+				require.Equal(t, "access-2", token.AccessToken)
+
+				stored, err := store.Load()
+				require.NoError(t, err)
+				require.Equal(t, "refresh-2", stored.RefreshToken)
+				require.Equal(t, "cloud", stored.CloudID)
+			},
+		},
+		{
+			name: "missing token file means not logged in",
+			test: func(t *testing.T) {
+				store := NewFileStore(filepath.Join(t.TempDir(), "token.json"))
+				source := NewTokenSource(OAuthConfig{}, http.DefaultClient, store)
+
+				_, err := source.Token(context.Background())
+				require.ErrorIs(t, err, ErrNotLoggedIn)
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.test(t)
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// refresh a bit before expiry so request in flight won't carry stale token
+const expiryDelta = time.Minute
+
+type FileStore struct {
+	path string
+}
+
+func NewFileStore(path string) TokenStoreType {
+	return &FileStore{path: path}
+}
+
+// TokenPath return location of token file of name under user config dir,
+// every profile keep its own grant
+func TokenPath(name string) (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "jira-workload-tui", "tokens", filepath.Base(name)+".json"), nil
+}
+
+// Load implements TokenStoreType.
+func (f *FileStore) Load() (*Token, error) {
+	data, err := os.ReadFile(f.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotLoggedIn
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var token Token
+	if err := json.Unmarshal(data, &token); err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// Save implements TokenStoreType.
+func (f *FileStore) Save(token *Token) error {
+	if err := os.MkdirAll(filepath.Dir(f.path), 0o700); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(token, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(f.path, data, 0o600)
+}
+
+type TokenSource struct {
+	mutex  sync.Mutex
+	config OAuthConfig
+	client *http.Client
+	store  TokenStoreType
+	token  *Token
+	now    func() time.Time
+}
+
+func NewTokenSource(
+	config OAuthConfig,
+	client *http.Client,
+	store TokenStoreType,
+) TokenSourceType {
+	return &TokenSource{
+		config: config,
+		client: client,
+		store:  store,
+		now:    time.Now,
+	}
+}
+
+// Token implements TokenSourceType.
+// It return stored token, refreshing and persisting it once it expires
+func (t *TokenSource) Token(ctx context.Context) (*Token, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.token == nil {
+		token, err := t.store.Load()
+		if err != nil {
+			return nil, err
+		}
+		t.token = token
+	}
+
+	if t.token.Expiry.IsZero() || t.now().Add(expiryDelta).Before(t.token.Expiry) {
+		return t.token, nil
+	}
+
+	token, err := t.refresh(ctx, t.token)
+	if err != nil {
+		return nil, err
+	}
+	t.token = token
+
+	return t.token, nil
+}
+
+// Invalidate implements TokenSourceType.
+// Next Token call refresh regardless of expiry, used after 401
+func (t *TokenSource) Invalidate() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.token != nil {
+		t.token.Expiry = t.now()
+	}
+}
+
+// CloudID implements TokenSourceType.
+// It return jira site id chosen at login, empty when not logged in
+func (t *TokenSource) CloudID() string {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.token == nil {
+		token, err := t.store.Load()
+		if err != nil {
+			return ""
+		}
+		t.token = token
+	}
+
+	return t.token.CloudID
+}
+
+func (t *TokenSource) refresh(ctx context.Context, current *Token) (*Token, error) {
+	if current.RefreshToken == "" {
+		return nil, ErrNotLoggedIn
+	}
+
+	token, err := Refresh(ctx, t.config, t.client, current.RefreshToken)
+	if err != nil {
+		return nil, err
+	}
+	token.CloudID = current.CloudID
+
+	if err := t.store.Save(token); err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
@@ -1,56 +1,334 @@
 package config
 
 import (
+	"errors"
+	"fmt"
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
+	"tui/auth"
 	"tui/utils"
 
 	"github.com/joho/godotenv"
 )
 
 type JiraCredConfig struct {
+	Profile        string
 	Email          string
 	UserToken      string
 	AtlassianURL   string
 	OrganizationID string
 	TeamID         string
-	JiraProject    string
+	Projects       []string
+	RequestTimeout time.Duration
+	MaxRetries     int
+	WorkingHours   int
+	Periods        []utils.Period
+	WorklogZone    string
+	SearchAPI      string
+	Deployment     string
+	UserGroup      string
+	UserList       []string
+	AuthMode       string
+	OAuth          auth.OAuthConfig
+	OAuthTokenPath string
 }
 
-func NewConfig() JiraConfigType {
-	err := godotenv.Load()
+const (
+	defaultRequestTimeout = 5 * time.Second
+	defaultMaxRetries     = 3
+	defaultOAuthRedirect  = "http://127.0.0.1:8089/callback"
+)
+
+// zone used to bucket worklogs into calendar days
+const (
+	WorklogZoneViewer = "viewer" // zone of viewed user jira profile
+	WorklogZoneAuthor = "author" // zone of worklog author jira profile
+	WorklogZoneUTC    = "utc"
+)
+
+// issue search endpoint, v3 search/jql is cloud only while data center
+// still serve v2 search
+const (
+	SearchAPIV2 = "v2"
+	SearchAPIV3 = "v3"
+)
+
+// kind of jira instance, data center authenticate with personal access
+// token and has no atlassian teams so roster come from group or user list
+const (
+	DeploymentCloud      = "cloud"
+	DeploymentDataCenter = "datacenter"
+)
+
+// how requests authenticate, oauth token is obtained by `tui auth login`
+// and kept in OAuthTokenPath instead of env
+const (
+	AuthModeToken = "token"
+	AuthModeOAuth = "oauth"
+)
+
+// NewConfig load config of profile, exiting when it is invalid. Empty
+// profile pick ATLASSIAN_PROFILE or default profile of config file
+func NewConfig(profile string, passphrase PassphraseFunc) JiraConfigType {
+	cfg, err := LoadConfig(profile, passphrase)
 	if err != nil {
-		log.Fatalf("error loading env: %v", err)
+		log.Fatalf("Error setup env config: %v", err)
 		return nil
 	}
 
-	email := os.Getenv("ATLASSIAN_USER_EMAIL")
-	userToken := os.Getenv("ATLASSIAN_USER_TOKEN")
-	baseURL := os.Getenv("ATLASSIAN_URL")
-	orgId := os.Getenv("ATLASSIAN_ORGANIZATION_ID")
-	teamId := os.Getenv("ATLASSIAN_TEAM_ID")
-	project := os.Getenv("ATLASSIAN_PROJECT")
-
-	if err := utils.ValidateENVs(map[string]string{
-		"email":     email,
-		"userToken": userToken,
-		"baseURL":   baseURL,
-		"orgId":     orgId,
-		"teamId":    teamId,
-		"project":   project,
-	}); err != nil {
-		log.Fatalf("Error setup env config: %v", err)
-		return nil
+	return cfg
+}
+
+// NewSetupConfig return cloud config holding only credentials, used to
+// talk to jira before profile exists
+func NewSetupConfig(baseURL string, email string, userToken string) JiraConfigType {
+	return &JiraCredConfig{
+		Email:          email,
+		UserToken:      userToken,
+		AtlassianURL:   strings.TrimSuffix(baseURL, "/"),
+		RequestTimeout: defaultRequestTimeout,
+		MaxRetries:     1,
+		WorkingHours:   utils.WORKING_HOURS,
+		Periods:        []utils.Period{{Name: utils.PeriodMonth, Kind: utils.PeriodMonth}},
+		WorklogZone:    WorklogZoneAuthor,
+		SearchAPI:      SearchAPIV3,
+		Deployment:     DeploymentCloud,
+		AuthMode:       AuthModeToken,
+	}
+}
+
+// LoadConfig read optional .env then resolve every setting from env, falling
+// back to value of the profile in config file. passphrase unlock token file
+// when profile keep its token in one
+func LoadConfig(profile string, passphrase PassphraseFunc) (JiraConfigType, error) {
+	if err := godotenv.Load(); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("error loading env: %v", err)
+	}
+
+	env, fileProfile, profileName, err := profileEnv(profile)
+	if err != nil {
+		return nil, err
+	}
+
+	email := env("ATLASSIAN_USER_EMAIL")
+	userToken := env("ATLASSIAN_USER_TOKEN")
+	baseURL := env("ATLASSIAN_URL")
+	orgId := env("ATLASSIAN_ORGANIZATION_ID")
+	teamId := env("ATLASSIAN_TEAM_ID")
+	projects := splitList(env("ATLASSIAN_PROJECT"))
+	userGroup := env("ATLASSIAN_USER_GROUP")
+	userList := splitList(env("ATLASSIAN_USERS"))
+
+	deployment := DeploymentCloud
+	if val := env("ATLASSIAN_DEPLOYMENT"); val != "" {
+		switch val {
+		case DeploymentCloud, DeploymentDataCenter:
+			deployment = val
+		default:
+			return nil, fmt.Errorf("invalid ATLASSIAN_DEPLOYMENT %q", val)
+		}
+	}
+
+	authMode := AuthModeToken
+	if val := env("ATLASSIAN_AUTH"); val != "" {
+		switch val {
+		case AuthModeToken, AuthModeOAuth:
+			authMode = val
+		default:
+			return nil, fmt.Errorf("invalid ATLASSIAN_AUTH %q", val)
+		}
+	}
+
+	if authMode == AuthModeOAuth && deployment == DeploymentDataCenter {
+		return nil, errors.New("ATLASSIAN_AUTH oauth is cloud only")
+	}
+
+	tokenSource := TokenSourceEnv
+	if val := env("ATLASSIAN_TOKEN_SOURCE"); val != "" {
+		switch val {
+		case TokenSourceEnv, TokenSourceHelper, TokenSourceFile, TokenSourceKeyring:
+			tokenSource = val
+		default:
+			return nil, fmt.Errorf("invalid ATLASSIAN_TOKEN_SOURCE %q", val)
+		}
+	}
+
+	if authMode == AuthModeToken {
+		token, err := resolveToken(env, tokenSource, baseURL, email, userToken, passphrase)
+		if err != nil {
+			return nil, err
+		}
+		userToken = token
+	}
+
+	requiredENVs := map[string]string{
+		"ATLASSIAN_USER_EMAIL":      email,
+		"ATLASSIAN_USER_TOKEN":      userToken,
+		"ATLASSIAN_URL":             baseURL,
+		"ATLASSIAN_ORGANIZATION_ID": orgId,
+		"ATLASSIAN_TEAM_ID":         teamId,
+	}
+	if deployment == DeploymentDataCenter {
+		// PAT carry the identity, roster is group or explicit user list
+		requiredENVs = map[string]string{
+			"ATLASSIAN_USER_TOKEN": userToken,
+			"ATLASSIAN_URL":        baseURL,
+		}
+	}
+
+	oauthConfig := auth.OAuthConfig{
+		ClientID:     env("ATLASSIAN_OAUTH_CLIENT_ID"),
+		ClientSecret: env("ATLASSIAN_OAUTH_CLIENT_SECRET"),
+		AuthURL:      auth.AtlassianAuthURL,
+		TokenURL:     auth.AtlassianTokenURL,
+		ResourcesURL: auth.AtlassianResourcesURL,
+		RedirectURL:  defaultOAuthRedirect,
+		Scopes:       auth.DefaultScopes,
+	}
+	if val := env("ATLASSIAN_OAUTH_REDIRECT_URL"); val != "" {
+		oauthConfig.RedirectURL = val
+	}
+
+	oauthTokenPath := ""
+	if authMode == AuthModeOAuth {
+		// token replace api token, identity come from oauth grant. Teams api
+		// only take api token, so roster is account id list instead
+		delete(requiredENVs, "ATLASSIAN_USER_EMAIL")
+		delete(requiredENVs, "ATLASSIAN_USER_TOKEN")
+		delete(requiredENVs, "ATLASSIAN_ORGANIZATION_ID")
+		delete(requiredENVs, "ATLASSIAN_TEAM_ID")
+		requiredENVs["ATLASSIAN_OAUTH_CLIENT_ID"] = oauthConfig.ClientID
+
+		// grant is bound to site, profile without name fall back to its host
+		name := profileName
+		if name == "" {
+			name = hostOf(baseURL)
+		}
+
+		path, err := auth.TokenPath(name)
+		if err != nil {
+			return nil, err
+		}
+		oauthTokenPath = path
+	}
+
+	if err := utils.ValidateENVs(requiredENVs); err != nil {
+		return nil, err
+	}
+
+	if deployment == DeploymentDataCenter && userGroup == "" && len(userList) == 0 {
+		return nil, errors.New("ATLASSIAN_USER_GROUP or ATLASSIAN_USERS is required for datacenter")
+	}
+
+	if authMode == AuthModeOAuth && len(userList) == 0 {
+		return nil, errors.New("ATLASSIAN_AUTH oauth can't read atlassian teams, set ATLASSIAN_USERS to account ids of the roster")
+	}
+
+	// optional, fallback to default when empty
+	requestTimeout := defaultRequestTimeout
+	if val := env("ATLASSIAN_REQUEST_TIMEOUT"); val != "" {
+		seconds, err := strconv.Atoi(val)
+		if err != nil || seconds <= 0 {
+			return nil, fmt.Errorf("invalid ATLASSIAN_REQUEST_TIMEOUT %q", val)
+		}
+		requestTimeout = time.Duration(seconds) * time.Second
+	}
+
+	maxRetries := defaultMaxRetries
+	if val := env("ATLASSIAN_MAX_RETRIES"); val != "" {
+		retries, err := strconv.Atoi(val)
+		if err != nil || retries < 0 {
+			return nil, fmt.Errorf("invalid ATLASSIAN_MAX_RETRIES %q", val)
+		}
+		maxRetries = retries
+	}
+
+	workingHours := utils.WORKING_HOURS
+	if val := env("ATLASSIAN_WORKING_HOURS"); val != "" {
+		hours, err := strconv.Atoi(val)
+		if err != nil || hours <= 0 || hours > 24 {
+			return nil, fmt.Errorf("invalid ATLASSIAN_WORKING_HOURS %q", val)
+		}
+		workingHours = hours
+	}
+
+	// ATLASSIAN_PERIODS replace periods of the profile as a whole
+	custom, err := profilePeriods(fileProfile.Periods)
+	if val := env("ATLASSIAN_PERIODS"); val != "" {
+		custom, err = parsePeriods(val)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	periods, err := selectPeriods(custom, env("ATLASSIAN_PERIOD"))
+	if err != nil {
+		return nil, err
+	}
+
+	worklogZone := WorklogZoneAuthor
+	if val := env("ATLASSIAN_WORKLOG_TIMEZONE"); val != "" {
+		switch val {
+		case WorklogZoneViewer, WorklogZoneAuthor, WorklogZoneUTC:
+			worklogZone = val
+		default:
+			return nil, fmt.Errorf("invalid ATLASSIAN_WORKLOG_TIMEZONE %q", val)
+		}
+	}
+
+	// cloud is retiring v2 search, data center only has it
+	searchAPI := SearchAPIV3
+	if deployment == DeploymentDataCenter {
+		searchAPI = SearchAPIV2
+	}
+	if val := env("ATLASSIAN_SEARCH_API"); val != "" {
+		switch val {
+		case SearchAPIV2, SearchAPIV3:
+			searchAPI = val
+			if deployment == DeploymentDataCenter && val == SearchAPIV3 {
+				return nil, errors.New("ATLASSIAN_SEARCH_API v3 is cloud only")
+			}
+		default:
+			return nil, fmt.Errorf("invalid ATLASSIAN_SEARCH_API %q", val)
+		}
 	}
 
 	return &JiraCredConfig{
+		Profile:        profileName,
 		Email:          email,
 		UserToken:      userToken,
 		AtlassianURL:   baseURL,
 		OrganizationID: orgId,
 		TeamID:         teamId,
-		JiraProject:    project,
+		Projects:       projects,
+		RequestTimeout: requestTimeout,
+		MaxRetries:     maxRetries,
+		WorkingHours:   workingHours,
+		Periods:        periods,
+		WorklogZone:    worklogZone,
+		SearchAPI:      searchAPI,
+		Deployment:     deployment,
+		UserGroup:      userGroup,
+		UserList:       userList,
+		AuthMode:       authMode,
+		OAuth:          oauthConfig,
+		OAuthTokenPath: oauthTokenPath,
+	}, nil
+}
+
+// splitList split comma separated env value, dropping empty items
+func splitList(val string) []string {
+	res := []string{}
+	for _, item := range strings.Split(val, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			res = append(res, item)
+		}
 	}
+
+	return res
 }
 
 // GetAtlassianURL implements JiraConfigType.
@@ -63,9 +341,10 @@ func (j *JiraCredConfig) GetEmail() string {
 	return j.Email
 }
 
-// GetJiraProject implements JiraConfigType.
-func (j *JiraCredConfig) GetJiraProject() string {
-	return j.JiraProject
+// GetProjects implements JiraConfigType.
+// Empty list mean every project visible to the user
+func (j *JiraCredConfig) GetProjects() []string {
+	return j.Projects
 }
 
 // GetOrgID implements JiraConfigType.
@@ -82,3 +361,78 @@ func (j *JiraCredConfig) GetTeamID() string {
 func (j *JiraCredConfig) GetUserToken() string {
 	return j.UserToken
 }
+
+// GetRequestTimeout implements JiraConfigType.
+func (j *JiraCredConfig) GetRequestTimeout() time.Duration {
+	return j.RequestTimeout
+}
+
+// GetMaxRetries implements JiraConfigType.
+func (j *JiraCredConfig) GetMaxRetries() int {
+	return j.MaxRetries
+}
+
+// GetWorklogZone implements JiraConfigType.
+func (j *JiraCredConfig) GetWorklogZone() string {
+	return j.WorklogZone
+}
+
+// GetSearchAPI implements JiraConfigType.
+func (j *JiraCredConfig) GetSearchAPI() string {
+	return j.SearchAPI
+}
+
+// GetDeployment implements JiraConfigType.
+func (j *JiraCredConfig) GetDeployment() string {
+	return j.Deployment
+}
+
+// GetUserGroup implements JiraConfigType.
+func (j *JiraCredConfig) GetUserGroup() string {
+	return j.UserGroup
+}
+
+// GetUserList implements JiraConfigType.
+func (j *JiraCredConfig) GetUserList() []string {
+	return j.UserList
+}
+
+// GetAuthMode implements JiraConfigType.
+func (j *JiraCredConfig) GetAuthMode() string {
+	return j.AuthMode
+}
+
+// GetOAuthConfig implements JiraConfigType.
+func (j *JiraCredConfig) GetOAuthConfig() auth.OAuthConfig {
+	return j.OAuth
+}
+
+// GetOAuthTokenPath implements JiraConfigType.
+func (j *JiraCredConfig) GetOAuthTokenPath() string {
+	return j.OAuthTokenPath
+}
+
+// GetProfile implements JiraConfigType.
+func (j *JiraCredConfig) GetProfile() string {
+	return j.Profile
+}
+
+// GetWorkingHours implements JiraConfigType.
+func (j *JiraCredConfig) GetWorkingHours() int {
+	return j.WorkingHours
+}
+
+// GetPeriods implements JiraConfigType.
+// Selected period come first, calendar month is always there
+func (j *JiraCredConfig) GetPeriods() []utils.Period {
+	return j.Periods
+}
+
+// WithTeam implements JiraConfigType.
+// It return copy of config pointing at another team of the same org
+func (j *JiraCredConfig) WithTeam(teamId string) JiraConfigType {
+	cfg := *j
+	cfg.TeamID = teamId
+
+	return &cfg
+}
@@ -1,10 +1,30 @@
 package config
 
+import (
+	"time"
+	"tui/auth"
+	"tui/utils"
+)
+
 type JiraConfigType interface {
+	GetProfile() string
 	GetEmail() string
 	GetUserToken() string
 	GetAtlassianURL() string
 	GetOrgID() string
 	GetTeamID() string
-	GetJiraProject() string
+	GetProjects() []string
+	GetRequestTimeout() time.Duration
+	GetMaxRetries() int
+	GetWorkingHours() int
+	GetPeriods() []utils.Period
+	GetWorklogZone() string
+	GetSearchAPI() string
+	GetDeployment() string
+	GetUserGroup() string
+	GetUserList() []string
+	GetAuthMode() string
+	GetOAuthConfig() auth.OAuthConfig
+	GetOAuthTokenPath() string
+	WithTeam(string) JiraConfigType
 }
@@ -0,0 +1,159 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"tui/utils"
+)
+
+// PeriodPolicy define named period of profile, cutoff use CutoffDay while
+// fiscal use Weeks and Start
+type PeriodPolicy struct {
+	Type      string `yaml:"type,omitempty"`       // cutoff | fiscal
+	CutoffDay int    `yaml:"cutoff_day,omitempty"` // last day of every period
+	Weeks     []int  `yaml:"weeks,omitempty"`      // weeks of months in quarter, like [4, 4, 5]
+	Start     string `yaml:"start,omitempty"`      // first day of any fiscal year, yyyy-mm-dd
+}
+
+// period validate policy and build period named name out of it
+func (p PeriodPolicy) period(name string) (utils.Period, error) {
+	switch p.Type {
+	case utils.PeriodCutoff:
+		if p.CutoffDay < 1 || p.CutoffDay > 28 {
+			return utils.Period{}, fmt.Errorf("cutoff day must be 1 - 28")
+		}
+
+		return utils.Period{Name: name, Kind: utils.PeriodCutoff, CutoffDay: p.CutoffDay}, nil
+	case utils.PeriodFiscal:
+		if len(p.Weeks) == 0 {
+			return utils.Period{}, fmt.Errorf("weeks must be positive numbers like 4-4-5")
+		}
+
+		for _, w := range p.Weeks {
+			if w <= 0 {
+				return utils.Period{}, fmt.Errorf("weeks must be positive numbers like 4-4-5")
+			}
+			if w*7 > utils.GRID_DAYS {
+				return utils.Period{}, fmt.Errorf("fiscal month can't be longer than %d weeks", utils.GRID_DAYS/7)
+			}
+		}
+
+		start, err := time.Parse(time.DateOnly, p.Start)
+		if err != nil {
+			return utils.Period{}, fmt.Errorf("start must be yyyy-mm-dd")
+		}
+
+		return utils.Period{Name: name, Kind: utils.PeriodFiscal, Weeks: p.Weeks, YearStart: start}, nil
+	}
+
+	return utils.Period{}, fmt.Errorf("unknown period type %q", p.Type)
+}
+
+// profilePeriods build periods of profile, sorted by name
+func profilePeriods(policies map[string]PeriodPolicy) ([]utils.Period, error) {
+	names := []string{}
+	for name := range policies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	periods := []utils.Period{}
+	for _, name := range names {
+		period, err := policies[name].period(name)
+		if err != nil {
+			return nil, fmt.Errorf("invalid period %q: %w", name, err)
+		}
+		periods = append(periods, period)
+	}
+
+	return periods, nil
+}
+
+// parsePeriods read ATLASSIAN_PERIODS, like
+// "payroll=cutoff:25, retail=fiscal:4-4-5:2023-12-31"
+func parsePeriods(val string) ([]utils.Period, error) {
+	periods := []utils.Period{}
+	for _, item := range splitList(val) {
+		name, policy, err := parsePeriod(item)
+		if err == nil {
+			var period utils.Period
+			period, err = policy.period(name)
+			periods = append(periods, period)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid ATLASSIAN_PERIODS %q: %w", item, err)
+		}
+	}
+
+	return periods, nil
+}
+
+func parsePeriod(item string) (string, PeriodPolicy, error) {
+	name, def, ok := strings.Cut(item, "=")
+	name = strings.TrimSpace(name)
+	if !ok || name == "" {
+		return "", PeriodPolicy{}, fmt.Errorf("expect name=type:options")
+	}
+
+	parts := strings.Split(strings.TrimSpace(def), ":")
+	switch parts[0] {
+	case utils.PeriodCutoff:
+		if len(parts) != 2 {
+			return "", PeriodPolicy{}, fmt.Errorf("expect %s=cutoff:<day>", name)
+		}
+
+		day, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return "", PeriodPolicy{}, fmt.Errorf("cutoff day must be 1 - 28")
+		}
+
+		return name, PeriodPolicy{Type: parts[0], CutoffDay: day}, nil
+	case utils.PeriodFiscal:
+		if len(parts) != 3 {
+			return "", PeriodPolicy{}, fmt.Errorf("expect %s=fiscal:<weeks>:<start>", name)
+		}
+
+		weeks := []int{}
+		for _, w := range strings.Split(parts[1], "-") {
+			n, err := strconv.Atoi(w)
+			if err != nil {
+				return "", PeriodPolicy{}, fmt.Errorf("weeks must be positive numbers like 4-4-5")
+			}
+			weeks = append(weeks, n)
+		}
+
+		return name, PeriodPolicy{Type: parts[0], Weeks: weeks, Start: parts[2]}, nil
+	}
+
+	return name, PeriodPolicy{Type: parts[0]}, nil
+}
+
+// selectPeriods put built in month before custom periods, selected period
+// come first so Date widget start with it
+func selectPeriods(custom []utils.Period, selected string) ([]utils.Period, error) {
+	periods := []utils.Period{{Name: utils.PeriodMonth, Kind: utils.PeriodMonth}}
+
+	for _, period := range custom {
+		for _, p := range periods {
+			if p.Name == period.Name {
+				return nil, fmt.Errorf("duplicate period %q", period.Name)
+			}
+		}
+		periods = append(periods, period)
+	}
+
+	if selected == "" {
+		return periods, nil
+	}
+
+	for i, period := range periods {
+		if period.Name == selected {
+			return append([]utils.Period{period}, append(periods[:i:i], periods[i+1:]...)...), nil
+		}
+	}
+
+	return nil, fmt.Errorf("invalid ATLASSIAN_PERIOD %q", selected)
+}
@@ -0,0 +1,123 @@
+package config
+
+import (
+	"testing"
+	"time"
+	"tui/utils"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePeriods(t *testing.T) {
+	month := utils.Period{Name: utils.PeriodMonth, Kind: utils.PeriodMonth}
+	payroll := utils.Period{Name: "payroll", Kind: utils.PeriodCutoff, CutoffDay: 25}
+	retail := utils.Period{
+		Name:      "retail",
+		Kind:      utils.PeriodFiscal,
+		Weeks:     []int{4, 4, 5},
+		YearStart: time.Date(2023, time.December, 31, 0, 0, 0, 0, time.UTC),
+	}
+
+	tcs := []struct {
+		name     string
+		val      string
+		selected string
+		expect   []utils.Period
+		err      bool
+	}{
+		{
+			name:   "only month",
+			expect: []utils.Period{month},
+		},
+		{
+			name:   "month come first by default",
+			val:    "payroll=cutoff:25, retail=fiscal:4-4-5:2023-12-31",
+			expect: []utils.Period{month, payroll, retail},
+		},
+		{
+			name:     "selected come first",
+			val:      "payroll=cutoff:25, retail=fiscal:4-4-5:2023-12-31",
+			selected: "retail",
+			expect:   []utils.Period{retail, month, payroll},
+		},
+		{name: "unknown selected", val: "payroll=cutoff:25", selected: "sprint", err: true},
+		{name: "cutoff not a number", val: "payroll=cutoff:last", err: true},
+		{name: "cutoff out of range", val: "payroll=cutoff:30", err: true},
+		{name: "fiscal month too long", val: "retail=fiscal:4-4-6:2023-12-31", err: true},
+		{name: "fiscal bad start", val: "retail=fiscal:4-4-5:31/12/2023", err: true},
+		{name: "unknown type", val: "sprint=weeks:2", err: true},
+		{name: "duplicate name", val: "month=cutoff:25", err: true},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := parsePeriods(tc.val)
+			if err == nil {
+				res, err = selectPeriods(res, tc.selected)
+			}
+			if tc.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expect, res)
+		})
+	}
+}
+
+func TestProfilePeriods(t *testing.T) {
+	tcs := []struct {
+		name     string
+		policies map[string]PeriodPolicy
+		expect   []utils.Period
+		err      bool
+	}{
+		{
+			name:   "no periods",
+			expect: []utils.Period{},
+		},
+		{
+			name: "sorted by name",
+			policies: map[string]PeriodPolicy{
+				"retail":  {Type: utils.PeriodFiscal, Weeks: []int{4, 4, 5}, Start: "2023-12-31"},
+				"payroll": {Type: utils.PeriodCutoff, CutoffDay: 25},
+			},
+			expect: []utils.Period{
+				{Name: "payroll", Kind: utils.PeriodCutoff, CutoffDay: 25},
+				{
+					Name:      "retail",
+					Kind:      utils.PeriodFiscal,
+					Weeks:     []int{4, 4, 5},
+					YearStart: time.Date(2023, time.December, 31, 0, 0, 0, 0, time.UTC),
+				},
+			},
+		},
+		{
+			name:     "cutoff out of range",
+			policies: map[string]PeriodPolicy{"payroll": {Type: utils.PeriodCutoff, CutoffDay: 31}},
+			err:      true,
+		},
+		{
+			name:     "fiscal without weeks",
+			policies: map[string]PeriodPolicy{"retail": {Type: utils.PeriodFiscal, Start: "2023-12-31"}},
+			err:      true,
+		},
+		{
+			name:     "missing type",
+			policies: map[string]PeriodPolicy{"payroll": {CutoffDay: 25}},
+			err:      true,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := profilePeriods(tc.policies)
+			if tc.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expect, res)
+		})
+	}
+}
@@ -0,0 +1,316 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// envLookup resolve setting by its env name
+type envLookup func(string) string
+
+// ProfileFile is config.yaml under $XDG_CONFIG_HOME/jira-workload-tui
+type ProfileFile struct {
+	DefaultProfile string             `yaml:"default_profile"`
+	Profiles       map[string]Profile `yaml:"profiles"`
+}
+
+type Profile struct {
+	Site            string                  `yaml:"site,omitempty"`
+	Deployment      string                  `yaml:"deployment,omitempty"`
+	Auth            ProfileAuth             `yaml:"auth,omitempty"`
+	Org             string                  `yaml:"org,omitempty"`
+	Team            string                  `yaml:"team,omitempty"`
+	Teams           []ProfileTeam           `yaml:"teams,omitempty"`
+	Group           string                  `yaml:"group,omitempty"`
+	Users           []string                `yaml:"users,omitempty"`
+	Projects        []string                `yaml:"projects,omitempty"`
+	SearchAPI       string                  `yaml:"search_api,omitempty"`
+	WorklogTimezone string                  `yaml:"worklog_timezone,omitempty"`
+	RequestTimeout  int                     `yaml:"request_timeout,omitempty"`
+	MaxRetries      *int                    `yaml:"max_retries,omitempty"`
+	WorkingHours    WorkingHoursPolicy      `yaml:"working_hours,omitempty"`
+	Period          string                  `yaml:"period,omitempty"`
+	Periods         map[string]PeriodPolicy `yaml:"periods,omitempty"`
+}
+
+type ProfileAuth struct {
+	Mode              string `yaml:"mode,omitempty"`
+	Email             string `yaml:"email,omitempty"`
+	TokenSource       string `yaml:"token_source,omitempty"`
+	CredentialHelper  string `yaml:"credential_helper,omitempty"`
+	TokenFile         string `yaml:"token_file,omitempty"`
+	OAuthClientID     string `yaml:"oauth_client_id,omitempty"`
+	OAuthClientSecret string `yaml:"oauth_client_secret,omitempty"`
+	OAuthRedirectURL  string `yaml:"oauth_redirect_url,omitempty"`
+}
+
+// ProfileTeam is extra team selectable from profile switcher
+type ProfileTeam struct {
+	Name string `yaml:"name,omitempty"`
+	ID   string `yaml:"id,omitempty"`
+}
+
+// SwitchEntry is single choice of profile switcher, empty TeamID keep team
+// of the profile
+type SwitchEntry struct {
+	Label   string
+	Profile string
+	TeamID  string
+}
+
+type WorkingHoursPolicy struct {
+	HoursPerDay int `yaml:"hours_per_day,omitempty"`
+}
+
+// ConfigFilePath return location of profile file, following XDG base dir
+func ConfigFilePath() (string, error) {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(home, ".config")
+	}
+
+	return filepath.Join(dir, "jira-workload-tui", "config.yaml"), nil
+}
+
+// LoadProfileFile read profile file, missing file return nil
+func LoadProfileFile(path string) (*ProfileFile, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var file ProfileFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+
+	return &file, nil
+}
+
+// LoadProfiles read profile file at ConfigFilePath, missing file return nil
+func LoadProfiles() (*ProfileFile, error) {
+	path, err := ConfigFilePath()
+	if err != nil {
+		return nil, err
+	}
+
+	return LoadProfileFile(path)
+}
+
+// NeedsSetup tell whether there is no profile to load yet, so setup wizard
+// should create it. Broken config file is left for LoadConfig to report
+func NeedsSetup(name string) bool {
+	if name == "" {
+		name = os.Getenv("ATLASSIAN_PROFILE")
+	}
+
+	file, err := LoadProfiles()
+	if err != nil {
+		return false
+	}
+	if file == nil || len(file.Profiles) == 0 {
+		return true
+	}
+	if name == "" {
+		return false
+	}
+
+	_, ok := file.Profiles[name]
+	return !ok
+}
+
+// SaveProfile write profile into config file under name, keeping other
+// profiles. It become default profile when asked or file has none
+func SaveProfile(name string, profile Profile, makeDefault bool) error {
+	path, err := ConfigFilePath()
+	if err != nil {
+		return err
+	}
+
+	file, err := LoadProfileFile(path)
+	if err != nil {
+		return err
+	}
+	if file == nil {
+		file = &ProfileFile{}
+	}
+	if file.Profiles == nil {
+		file.Profiles = map[string]Profile{}
+	}
+
+	file.Profiles[name] = profile
+	if makeDefault || file.DefaultProfile == "" {
+		file.DefaultProfile = name
+	}
+
+	data, err := yaml.Marshal(file)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0o600)
+}
+
+// WithSetup return p updated with answers of setup wizard, keeping settings
+// the wizard doesn't ask for like deployment, auth mode, teams or periods
+func (p Profile) WithSetup(setup Profile) Profile {
+	p.Site = setup.Site
+	p.Auth.Email = setup.Auth.Email
+	p.Auth.TokenSource = setup.Auth.TokenSource
+	p.Auth.TokenFile = setup.Auth.TokenFile
+	p.Org = setup.Org
+	p.Team = setup.Team
+	p.Projects = setup.Projects
+
+	return p
+}
+
+// ProfileNames return sorted names of profiles in the file
+func (f *ProfileFile) ProfileNames() []string {
+	names := []string{}
+	if f == nil {
+		return names
+	}
+
+	for name := range f.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// SwitchEntries list every profile followed by its extra teams, read fresh
+// from config file so edits show up without restart
+func SwitchEntries() ([]SwitchEntry, error) {
+	path, err := ConfigFilePath()
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := LoadProfileFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []SwitchEntry{}
+	for _, name := range file.ProfileNames() {
+		entries = append(entries, SwitchEntry{Label: name, Profile: name})
+
+		for _, team := range file.Profiles[name].Teams {
+			entries = append(entries, SwitchEntry{
+				Label:   fmt.Sprintf("%s / %s", name, team.Name),
+				Profile: name,
+				TeamID:  team.ID,
+			})
+		}
+	}
+
+	return entries, nil
+}
+
+// envs map profile into env names read by LoadConfig
+func (p Profile) envs() map[string]string {
+	res := map[string]string{
+		"ATLASSIAN_URL":                 p.Site,
+		"ATLASSIAN_DEPLOYMENT":          p.Deployment,
+		"ATLASSIAN_AUTH":                p.Auth.Mode,
+		"ATLASSIAN_USER_EMAIL":          p.Auth.Email,
+		"ATLASSIAN_TOKEN_SOURCE":        p.Auth.TokenSource,
+		"ATLASSIAN_CREDENTIAL_HELPER":   p.Auth.CredentialHelper,
+		"ATLASSIAN_TOKEN_FILE":          p.Auth.TokenFile,
+		"ATLASSIAN_OAUTH_CLIENT_ID":     p.Auth.OAuthClientID,
+		"ATLASSIAN_OAUTH_CLIENT_SECRET": p.Auth.OAuthClientSecret,
+		"ATLASSIAN_OAUTH_REDIRECT_URL":  p.Auth.OAuthRedirectURL,
+		"ATLASSIAN_ORGANIZATION_ID":     p.Org,
+		"ATLASSIAN_TEAM_ID":             p.Team,
+		"ATLASSIAN_USER_GROUP":          p.Group,
+		"ATLASSIAN_USERS":               strings.Join(p.Users, ","),
+		"ATLASSIAN_PROJECT":             strings.Join(p.Projects, ", "),
+		"ATLASSIAN_SEARCH_API":          p.SearchAPI,
+		"ATLASSIAN_WORKLOG_TIMEZONE":    p.WorklogTimezone,
+		"ATLASSIAN_PERIOD":              p.Period,
+	}
+
+	if p.RequestTimeout > 0 {
+		res["ATLASSIAN_REQUEST_TIMEOUT"] = strconv.Itoa(p.RequestTimeout)
+	}
+	if p.MaxRetries != nil {
+		res["ATLASSIAN_MAX_RETRIES"] = strconv.Itoa(*p.MaxRetries)
+	}
+	if p.WorkingHours.HoursPerDay > 0 {
+		res["ATLASSIAN_WORKING_HOURS"] = strconv.Itoa(p.WorkingHours.HoursPerDay)
+	}
+
+	return res
+}
+
+// profileEnv pick profile by name, ATLASSIAN_PROFILE or default_profile and
+// return it with lookup where env var override value of the profile. Without
+// config file only env is used
+func profileEnv(name string) (envLookup, Profile, string, error) {
+	if name == "" {
+		name = os.Getenv("ATLASSIAN_PROFILE")
+	}
+
+	path, err := ConfigFilePath()
+	if err != nil {
+		return nil, Profile{}, "", err
+	}
+
+	file, err := LoadProfileFile(path)
+	if err != nil {
+		return nil, Profile{}, "", err
+	}
+
+	if file == nil {
+		if name != "" {
+			return nil, Profile{}, "", fmt.Errorf("profile %q requested but %s does not exist", name, path)
+		}
+		return os.Getenv, Profile{}, "", nil
+	}
+
+	if name == "" {
+		name = file.DefaultProfile
+	}
+	if name == "" && len(file.Profiles) == 1 {
+		name = file.ProfileNames()[0]
+	}
+	if name == "" {
+		return os.Getenv, Profile{}, "", nil
+	}
+
+	profile, ok := file.Profiles[name]
+	if !ok {
+		return nil, Profile{}, "", fmt.Errorf("unknown profile %q, available: %s", name, strings.Join(file.ProfileNames(), ", "))
+	}
+
+	values := profile.envs()
+	lookup := func(key string) string {
+		if val := os.Getenv(key); val != "" {
+			return val
+		}
+
+		return values[key]
+	}
+
+	return lookup, profile, name, nil
+}
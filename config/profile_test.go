@@ -0,0 +1,420 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"tui/utils"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testProfileFile = `
+default_profile: work
+profiles:
+  work:
+    site: https://acme.atlassian.net
+    auth:
+      email: dev@acme.com
+    org: org-1
+    team: team-1
+    teams:
+      - name: Platform
+        id: team-2
+    projects: [TUI, OPS]
+    max_retries: 0
+    working_hours:
+      hours_per_day: 7
+    period: payroll
+    periods:
+      payroll:
+        type: cutoff
+        cutoff_day: 25
+  onprem:
+    site: https://jira.acme.internal
+    deployment: datacenter
+    group: developers
+    projects: [LEGACY]
+`
+
+func writeProfileFile(t *testing.T) {
+	t.Helper()
+
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("ATLASSIAN_PROFILE", "")
+
+	path := filepath.Join(dir, "jira-workload-tui", "config.yaml")
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o700))
+	require.NoError(t, os.WriteFile(path, []byte(testProfileFile), 0o600))
+}
+
+func TestProfileEnv(t *testing.T) {
+	tcs := []struct {
+		name    string
+		profile string
+		env     map[string]string
+		expect  map[string]string
+		err     bool
+	}{
+		{
+			name:    "default profile",
+			profile: "",
+			expect: map[string]string{
+				"ATLASSIAN_URL":           "https://acme.atlassian.net",
+				"ATLASSIAN_PROJECT":       "TUI, OPS",
+				"ATLASSIAN_MAX_RETRIES":   "0",
+				"ATLASSIAN_WORKING_HOURS": "7",
+				"ATLASSIAN_DEPLOYMENT":    "",
+			},
+		},
+		{
+			name:    "named profile",
+			profile: "onprem",
+			expect: map[string]string{
+				"ATLASSIAN_URL":         "https://jira.acme.internal",
+				"ATLASSIAN_DEPLOYMENT":  "datacenter",
+				"ATLASSIAN_USER_GROUP":  "developers",
+				"ATLASSIAN_MAX_RETRIES": "",
+			},
+		},
+		{
+			name:    "env override profile",
+			profile: "work",
+			env:     map[string]string{"ATLASSIAN_PROJECT": "HOTFIX"},
+			expect:  map[string]string{"ATLASSIAN_PROJECT": "HOTFIX"},
+		},
+		{
+			name:    "unknown profile",
+			profile: "missing",
+			err:     true,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			writeProfileFile(t)
+			for key := range tc.expect {
+				t.Setenv(key, "")
+			}
+			for key, val := range tc.env {
+				t.Setenv(key, val)
+			}
+
+			env, _, _, err := profileEnv(tc.profile)
+			if tc.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			for key, val := range tc.expect {
+				require.Equal(t, val, env(key), key)
+			}
+		})
+	}
+}
+
+func TestLoadConfigProfile(t *testing.T) {
+	writeProfileFile(t)
+	for _, key := range []string{"ATLASSIAN_URL", "ATLASSIAN_USER_EMAIL", "ATLASSIAN_ORGANIZATION_ID", "ATLASSIAN_TEAM_ID", "ATLASSIAN_PROJECT", "ATLASSIAN_DEPLOYMENT", "ATLASSIAN_AUTH", "ATLASSIAN_TOKEN_SOURCE", "ATLASSIAN_WORKING_HOURS", "ATLASSIAN_PERIOD", "ATLASSIAN_PERIODS"} {
+		t.Setenv(key, "")
+	}
+	t.Setenv("ATLASSIAN_USER_TOKEN", "token")
+
+	cfg, err := LoadConfig("work", PromptPassphrase)
+	require.NoError(t, err)
+	require.Equal(t, "work", cfg.GetProfile())
+	require.Equal(t, "https://acme.atlassian.net", cfg.GetAtlassianURL())
+	require.Equal(t, []string{"TUI", "OPS"}, cfg.GetProjects())
+	require.Equal(t, 0, cfg.GetMaxRetries())
+	require.Equal(t, 7, cfg.GetWorkingHours())
+	require.Equal(t, []utils.Period{
+		{Name: "payroll", Kind: utils.PeriodCutoff, CutoffDay: 25},
+		{Name: utils.PeriodMonth, Kind: utils.PeriodMonth},
+	}, cfg.GetPeriods())
+}
+
+func TestLoadConfigOAuthTokenPath(t *testing.T) {
+	tcs := []struct {
+		name    string
+		file    bool
+		profile string
+		env     map[string]string
+		expect  string
+	}{
+		{
+			name:    "named profile",
+			file:    true,
+			profile: "work",
+			expect:  "tokens/work.json",
+		},
+		{
+			name: "env only fall back to host",
+			env: map[string]string{
+				"ATLASSIAN_URL": "https://other.atlassian.net",
+			},
+			expect: "tokens/other.atlassian.net.json",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, key := range []string{"ATLASSIAN_URL", "ATLASSIAN_USER_EMAIL", "ATLASSIAN_ORGANIZATION_ID", "ATLASSIAN_TEAM_ID", "ATLASSIAN_DEPLOYMENT", "ATLASSIAN_TOKEN_SOURCE", "ATLASSIAN_PERIOD", "ATLASSIAN_PERIODS"} {
+				t.Setenv(key, "")
+			}
+			if tc.file {
+				writeProfileFile(t)
+			} else {
+				t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+				t.Setenv("ATLASSIAN_PROFILE", "")
+			}
+			t.Setenv("ATLASSIAN_AUTH", AuthModeOAuth)
+			t.Setenv("ATLASSIAN_OAUTH_CLIENT_ID", "client-1")
+			t.Setenv("ATLASSIAN_USERS", "acc-1")
+			for key, val := range tc.env {
+				t.Setenv(key, val)
+			}
+
+			cfg, err := LoadConfig(tc.profile, PromptPassphrase)
+			require.NoError(t, err)
+			require.Equal(
+				t,
+				filepath.Join(os.Getenv("XDG_CONFIG_HOME"), "jira-workload-tui", filepath.FromSlash(tc.expect)),
+				cfg.GetOAuthTokenPath(),
+			)
+		})
+	}
+}
+
+func TestLoadConfigOAuthRoster(t *testing.T) {
+	tcs := []struct {
+		name   string
+		users  string
+		expect []string
+		err    bool
+	}{
+		{name: "team roster is rejected", err: true},
+		{name: "account id roster", users: "acc-1, acc-2", expect: []string{"acc-1", "acc-2"}},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+			for _, key := range []string{"ATLASSIAN_PROFILE", "ATLASSIAN_USER_EMAIL", "ATLASSIAN_USER_TOKEN", "ATLASSIAN_ORGANIZATION_ID", "ATLASSIAN_DEPLOYMENT", "ATLASSIAN_TOKEN_SOURCE", "ATLASSIAN_PERIOD", "ATLASSIAN_PERIODS"} {
+				t.Setenv(key, "")
+			}
+			t.Setenv("ATLASSIAN_URL", "https://acme.atlassian.net")
+			t.Setenv("ATLASSIAN_TEAM_ID", "team-1")
+			t.Setenv("ATLASSIAN_AUTH", AuthModeOAuth)
+			t.Setenv("ATLASSIAN_OAUTH_CLIENT_ID", "client-1")
+			t.Setenv("ATLASSIAN_USERS", tc.users)
+
+			cfg, err := LoadConfig("", PromptPassphrase)
+			if tc.err {
+				require.ErrorContains(t, err, "ATLASSIAN_USERS")
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expect, cfg.GetUserList())
+		})
+	}
+}
+
+func TestLoadConfigSearchAPI(t *testing.T) {
+	tcs := []struct {
+		name    string
+		profile string
+		env     string
+		expect  string
+		err     bool
+	}{
+		{name: "cloud default to v3", profile: "work", expect: SearchAPIV3},
+		{name: "cloud keep v2 when asked", profile: "work", env: SearchAPIV2, expect: SearchAPIV2},
+		{name: "data center default to v2", profile: "onprem", expect: SearchAPIV2},
+		{name: "data center has no v3", profile: "onprem", env: SearchAPIV3, err: true},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			writeProfileFile(t)
+			for _, key := range []string{"ATLASSIAN_URL", "ATLASSIAN_USER_EMAIL", "ATLASSIAN_ORGANIZATION_ID", "ATLASSIAN_TEAM_ID", "ATLASSIAN_DEPLOYMENT", "ATLASSIAN_AUTH", "ATLASSIAN_TOKEN_SOURCE", "ATLASSIAN_PERIOD", "ATLASSIAN_PERIODS"} {
+				t.Setenv(key, "")
+			}
+			t.Setenv("ATLASSIAN_USER_TOKEN", "token")
+			t.Setenv("ATLASSIAN_SEARCH_API", tc.env)
+
+			cfg, err := LoadConfig(tc.profile, PromptPassphrase)
+			if tc.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expect, cfg.GetSearchAPI())
+		})
+	}
+}
+
+func TestSwitchEntries(t *testing.T) {
+	writeProfileFile(t)
+
+	entries, err := SwitchEntries()
+	require.NoError(t, err)
+	require.Equal(t, []SwitchEntry{
+		{Label: "onprem", Profile: "onprem"},
+		{Label: "work", Profile: "work"},
+		{Label: "work / Platform", Profile: "work", TeamID: "team-2"},
+	}, entries)
+
+	cfg := &JiraCredConfig{Profile: "work", TeamID: "team-1"}
+	switched := cfg.WithTeam("team-2")
+	require.Equal(t, "team-2", switched.GetTeamID())
+	require.Equal(t, "team-1", cfg.GetTeamID())
+}
+
+func TestSaveProfile(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("ATLASSIAN_PROFILE", "")
+
+	require.NoError(t, SaveProfile("home", Profile{
+		Site:     "https://home.atlassian.net",
+		Auth:     ProfileAuth{Email: "dev@home.com", TokenSource: TokenSourceKeyring},
+		Team:     "team-1",
+		Projects: []string{"HOME"},
+	}, false))
+	require.NoError(t, SaveProfile("work", Profile{Site: "https://acme.atlassian.net"}, false))
+
+	path, err := ConfigFilePath()
+	require.NoError(t, err)
+
+	file, err := LoadProfileFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "home", file.DefaultProfile)
+	require.Equal(t, []string{"home", "work"}, file.ProfileNames())
+
+	lookup, _, name, err := profileEnv("")
+	require.NoError(t, err)
+	require.Equal(t, "home", name)
+	require.Equal(t, "keyring", lookup("ATLASSIAN_TOKEN_SOURCE"))
+	require.Equal(t, "HOME", lookup("ATLASSIAN_PROJECT"))
+}
+
+func TestSaveProfileOverwrite(t *testing.T) {
+	tcs := []struct {
+		name          string
+		profile       string
+		makeDefault   bool
+		expectDefault string
+	}{
+		{
+			name:          "update keep default profile",
+			profile:       "onprem",
+			expectDefault: "work",
+		},
+		{
+			name:          "update and make it default",
+			profile:       "onprem",
+			makeDefault:   true,
+			expectDefault: "onprem",
+		},
+		{
+			name:          "new profile keep default profile",
+			profile:       "home",
+			expectDefault: "work",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			writeProfileFile(t)
+
+			require.NoError(t, SaveProfile(tc.profile, Profile{Site: "https://new.atlassian.net"}, tc.makeDefault))
+
+			file, err := LoadProfiles()
+			require.NoError(t, err)
+			require.Equal(t, tc.expectDefault, file.DefaultProfile)
+			require.Equal(t, "https://new.atlassian.net", file.Profiles[tc.profile].Site)
+			require.Equal(t, "https://acme.atlassian.net", file.Profiles["work"].Site)
+			require.Len(t, file.Profiles["work"].Teams, 1)
+		})
+	}
+}
+
+func TestProfileWithSetup(t *testing.T) {
+	writeProfileFile(t)
+
+	file, err := LoadProfiles()
+	require.NoError(t, err)
+
+	res := file.Profiles["work"].WithSetup(Profile{
+		Site:     "https://new.atlassian.net",
+		Auth:     ProfileAuth{Email: "new@acme.com", TokenSource: TokenSourceKeyring},
+		Org:      "org-2",
+		Team:     "team-3",
+		Projects: []string{"NEW"},
+	})
+
+	require.Equal(t, "https://new.atlassian.net", res.Site)
+	require.Equal(t, ProfileAuth{Email: "new@acme.com", TokenSource: TokenSourceKeyring}, res.Auth)
+	require.Equal(t, "team-3", res.Team)
+	require.Equal(t, []string{"NEW"}, res.Projects)
+	require.Equal(t, []ProfileTeam{{Name: "Platform", ID: "team-2"}}, res.Teams)
+	require.Equal(t, 7, res.WorkingHours.HoursPerDay)
+	require.Equal(t, "payroll", res.Period)
+	require.Contains(t, res.Periods, "payroll")
+
+	// wizard doesn't ask for deployment nor auth mode
+	onprem := file.Profiles["onprem"]
+	onprem.Auth.Mode = AuthModeOAuth
+	res = onprem.WithSetup(Profile{
+		Site: "https://jira.acme.example",
+		Auth: ProfileAuth{Email: "dev@acme.com", TokenSource: TokenSourceEnv},
+	})
+	require.Equal(t, "https://jira.acme.example", res.Site)
+	require.Equal(t, "datacenter", res.Deployment)
+	require.Equal(t, AuthModeOAuth, res.Auth.Mode)
+	require.Equal(t, TokenSourceEnv, res.Auth.TokenSource)
+}
+
+func TestNeedsSetup(t *testing.T) {
+	tcs := []struct {
+		name    string
+		file    bool
+		profile string
+		expect  bool
+	}{
+		{
+			name:   "no config file",
+			expect: true,
+		},
+		{
+			name:    "missing profile",
+			file:    true,
+			profile: "home",
+			expect:  true,
+		},
+		{
+			name:    "existing profile",
+			file:    true,
+			profile: "onprem",
+		},
+		{
+			name: "default profile",
+			file: true,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.file {
+				writeProfileFile(t)
+			} else {
+				t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+				t.Setenv("ATLASSIAN_PROFILE", "")
+			}
+
+			require.Equal(t, tc.expect, NeedsSetup(tc.profile))
+		})
+	}
+}
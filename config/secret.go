@@ -0,0 +1,333 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/joho/godotenv"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/term"
+)
+
+// where api token is read from, env keep reading ATLASSIAN_USER_TOKEN
+const (
+	TokenSourceEnv     = "env"
+	TokenSourceHelper  = "helper"
+	TokenSourceFile    = "file"
+	TokenSourceKeyring = "keyring"
+)
+
+// keyring entries are stored under this service, keyed by site url
+const keyringService = "jira-workload-tui"
+
+const (
+	tokenFileVersion = "v1"
+	tokenFileSaltLen = 16
+	tokenFileIter    = 600000
+)
+
+var ErrWrongPassphrase = errors.New("wrong passphrase or corrupted token file")
+
+// PassphraseFunc supply passphrase of token file at path, it is only called
+// for profiles keeping their token in one
+type PassphraseFunc func(path string) (string, error)
+
+// resolveToken read api token from configured source, falling back to
+// ATLASSIAN_USER_TOKEN when the source has no token stored
+func resolveToken(env envLookup, source string, baseURL string, email string, envToken string, passphrase PassphraseFunc) (string, error) {
+	var token string
+	var err error
+
+	switch source {
+	case TokenSourceHelper:
+		helper := env("ATLASSIAN_CREDENTIAL_HELPER")
+		if helper == "" {
+			return "", errors.New("ATLASSIAN_CREDENTIAL_HELPER is required for helper token source")
+		}
+		token, err = tokenFromHelper(helper, baseURL, email)
+	case TokenSourceFile:
+		path := env("ATLASSIAN_TOKEN_FILE")
+		if path == "" {
+			return "", errors.New("ATLASSIAN_TOKEN_FILE is required for file token source")
+		}
+		token, err = tokenFromFile(path, passphrase)
+		if errors.Is(err, os.ErrNotExist) {
+			token, err = "", nil
+		}
+	case TokenSourceKeyring:
+		token, err = tokenFromKeyring(baseURL)
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	if token == "" {
+		return envToken, nil
+	}
+
+	return token, nil
+}
+
+// tokenFromHelper run credential helper the same way git does, `<helper> get`
+// through shell with protocol/host/username on stdin. Helper answer with
+// password=<token> line, or plain token as its only output
+func tokenFromHelper(helper string, baseURL string, email string) (string, error) {
+	input := fmt.Sprintf("protocol=https\nhost=%s\n", hostOf(baseURL))
+	if email != "" {
+		input += fmt.Sprintf("username=%s\n", email)
+	}
+	input += "\n"
+
+	var stderr bytes.Buffer
+	cmd := exec.Command("sh", "-c", helper+" get")
+	cmd.Stdin = strings.NewReader(input)
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("credential helper: %v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return parseHelperOutput(out), nil
+}
+
+func parseHelperOutput(out []byte) string {
+	first := ""
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		if token, ok := strings.CutPrefix(line, "password="); ok {
+			return token
+		}
+		if first == "" && !strings.Contains(line, "=") {
+			first = line
+		}
+	}
+
+	return first
+}
+
+// tokenFromKeyring look up token in Secret Service through secret-tool, or
+// macOS keychain through security. Missing entry return empty token
+func tokenFromKeyring(baseURL string) (string, error) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("security", "find-generic-password", "-s", keyringService, "-a", baseURL, "-w")
+	default:
+		cmd = exec.Command("secret-tool", "lookup", "service", keyringService, "url", baseURL)
+	}
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && keyringMissing(runtime.GOOS, exitErr.ExitCode(), append(out, stderr.Bytes()...)) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("keyring: %v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
+
+// keyringMissing tell whether lookup failed only because entry is missing,
+// security exit 44 (errSecItemNotFound) while secret-tool exit 1 without
+// printing anything
+func keyringMissing(goos string, code int, out []byte) bool {
+	if goos == "darwin" {
+		return code == 44
+	}
+
+	return code == 1 && len(bytes.TrimSpace(out)) == 0
+}
+
+// tokenFromFile decrypt token file with passphrase of it
+func tokenFromFile(path string, passphrase PassphraseFunc) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	secret, err := passphrase(path)
+	if err != nil {
+		return "", err
+	}
+
+	return DecryptToken(data, secret)
+}
+
+// PromptPassphrase ask for passphrase of token file on terminal
+func PromptPassphrase(path string) (string, error) {
+	return ReadSecret("Token file passphrase: ")
+}
+
+// StoreTokenKeyring save token into keyring entry read by tokenFromKeyring
+func StoreTokenKeyring(baseURL string, token string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		// -w without value make security prompt for it twice, keeping token
+		// out of process list
+		cmd = exec.Command("security", "add-generic-password", "-U", "-s", keyringService, "-a", baseURL, "-w")
+		cmd.Stdin = strings.NewReader(token + "\n" + token + "\n")
+	default:
+		cmd = exec.Command("secret-tool", "store", "--label=Jira workload token", "service", keyringService, "url", baseURL)
+		cmd.Stdin = strings.NewReader(token)
+	}
+
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("keyring: %v: %s", err, strings.TrimSpace(string(out)))
+	}
+
+	return nil
+}
+
+// StoreTokenFile encrypt token into path read by tokenFromFile
+func StoreTokenFile(path string, token string, passphrase string) error {
+	data, err := EncryptToken(token, passphrase)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0o600)
+}
+
+// StoreTokenEnv write token as ATLASSIAN_USER_TOKEN into .env of working
+// directory, keeping the other values. The token is set in the process env
+// too since godotenv.Load doesn't override variable already set
+func StoreTokenEnv(token string) error {
+	envs, err := godotenv.Read()
+	if errors.Is(err, os.ErrNotExist) {
+		envs, err = map[string]string{}, nil
+	}
+	if err != nil {
+		return err
+	}
+
+	envs["ATLASSIAN_USER_TOKEN"] = token
+	if err := godotenv.Write(envs, ".env"); err != nil {
+		return err
+	}
+	if err := os.Chmod(".env", 0o600); err != nil {
+		return err
+	}
+
+	return os.Setenv("ATLASSIAN_USER_TOKEN", token)
+}
+
+// ReadSecret prompt on terminal without echoing input
+func ReadSecret(prompt string) (string, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return "", errors.New("no terminal to prompt for secret")
+	}
+
+	fmt.Fprint(os.Stderr, prompt)
+	secret, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(secret)), nil
+}
+
+// EncryptToken seal token with AES-256-GCM keyed by PBKDF2 of passphrase,
+// output is single `v1:<base64 salt|nonce|ciphertext>` line
+func EncryptToken(token string, passphrase string) ([]byte, error) {
+	salt := make([]byte, tokenFileSaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	gcm, err := newTokenCipher(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	sealed := append(append(salt, nonce...), gcm.Seal(nil, nonce, []byte(token), nil)...)
+	return []byte(fmt.Sprintf("%s:%s\n", tokenFileVersion, base64.StdEncoding.EncodeToString(sealed))), nil
+}
+
+// DecryptToken open file content made by EncryptToken
+func DecryptToken(data []byte, passphrase string) (string, error) {
+	encoded, ok := strings.CutPrefix(strings.TrimSpace(string(data)), tokenFileVersion+":")
+	if !ok {
+		return "", errors.New("unknown token file format")
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", ErrWrongPassphrase
+	}
+
+	if len(sealed) < tokenFileSaltLen {
+		return "", ErrWrongPassphrase
+	}
+	salt := sealed[:tokenFileSaltLen]
+
+	gcm, err := newTokenCipher(passphrase, salt)
+	if err != nil {
+		return "", err
+	}
+
+	rest := sealed[tokenFileSaltLen:]
+	if len(rest) < gcm.NonceSize() {
+		return "", ErrWrongPassphrase
+	}
+
+	token, err := gcm.Open(nil, rest[:gcm.NonceSize()], rest[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", ErrWrongPassphrase
+	}
+
+	return string(token), nil
+}
+
+func newTokenCipher(passphrase string, salt []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(pbkdf2.Key([]byte(passphrase), salt, tokenFileIter, 32, sha256.New))
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+func hostOf(baseURL string) string {
+	parsed, err := url.Parse(baseURL)
+	if err != nil || parsed.Host == "" {
+		return baseURL
+	}
+
+	return parsed.Host
+}
@@ -0,0 +1,168 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncryptToken(t *testing.T) {
+	data, err := EncryptToken("secret-token", "passphrase")
+	require.NoError(t, err)
+	require.NotContains(t, string(data), "secret-token")
+
+	token, err := DecryptToken(data, "passphrase")
+	require.NoError(t, err)
+	require.Equal(t, "secret-token", token)
+
+	_, err = DecryptToken(data, "other")
+	require.ErrorIs(t, err, ErrWrongPassphrase)
+
+	// files written by earlier versions must keep opening
+	token, err = DecryptToken([]byte("v1:G3YksSEDH1tB5BuA5w3UtHIT0qrUrK0Z+wfSi8hdBV/j8sNxeloDj7hpcKPiarNY49YWTOTErVU=\n"), "passphrase")
+	require.NoError(t, err)
+	require.Equal(t, "golden-token", token)
+}
+
+func TestResolveToken(t *testing.T) {
+	tcs := []struct {
+		name   string
+		source string
+		env    map[string]string
+		expect string
+	}{
+		{
+			name:   "env source keep env token",
+			source: TokenSourceEnv,
+			expect: "env-token",
+		},
+		{
+			name:   "git style helper",
+			source: TokenSourceHelper,
+			env: map[string]string{
+				"ATLASSIAN_CREDENTIAL_HELPER": `f() { cat >/dev/null; printf 'protocol=https\nhost=example.atlassian.net\npassword=helper-token\n'; }; f`,
+			},
+			expect: "helper-token",
+		},
+		{
+			name:   "plain output helper",
+			source: TokenSourceHelper,
+			env: map[string]string{
+				"ATLASSIAN_CREDENTIAL_HELPER": `f() { echo plain-token; }; f`,
+			},
+			expect: "plain-token",
+		},
+		{
+			name:   "helper without token fallback to env",
+			source: TokenSourceHelper,
+			env: map[string]string{
+				"ATLASSIAN_CREDENTIAL_HELPER": `true`,
+			},
+			expect: "env-token",
+		},
+		{
+			name:   "missing token file fallback to env",
+			source: TokenSourceFile,
+			env: map[string]string{
+				"ATLASSIAN_TOKEN_FILE": filepath.Join(os.TempDir(), "jira-workload-tui-missing-token"),
+			},
+			expect: "env-token",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			for key, val := range tc.env {
+				t.Setenv(key, val)
+			}
+
+			token, err := resolveToken(os.Getenv, tc.source, "https://example.atlassian.net", "dev@example.com", "env-token", PromptPassphrase)
+			require.NoError(t, err)
+			require.Equal(t, tc.expect, token)
+		})
+	}
+
+	t.Run("encrypted token file", func(t *testing.T) {
+		data, err := EncryptToken("file-token", "passphrase")
+		require.NoError(t, err)
+
+		path := filepath.Join(t.TempDir(), "token")
+		require.NoError(t, os.WriteFile(path, data, 0o600))
+		t.Setenv("ATLASSIAN_TOKEN_FILE", path)
+
+		passphrase := func(file string) (string, error) {
+			require.Equal(t, path, file)
+			return "passphrase", nil
+		}
+
+		token, err := resolveToken(os.Getenv, TokenSourceFile, "https://example.atlassian.net", "", "env-token", passphrase)
+		require.NoError(t, err)
+		require.Equal(t, "file-token", token)
+	})
+}
+
+func TestStoreTokenEnv(t *testing.T) {
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	dir := t.TempDir()
+	require.NoError(t, os.Chdir(dir))
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	require.NoError(t, os.WriteFile(".env", []byte("ATLASSIAN_URL=https://acme.atlassian.net\nATLASSIAN_USER_TOKEN=stale-token\n"), 0o600))
+	t.Setenv("ATLASSIAN_USER_TOKEN", "stale-token")
+
+	require.NoError(t, StoreTokenEnv("new-token"))
+	require.Equal(t, "new-token", os.Getenv("ATLASSIAN_USER_TOKEN"))
+
+	data, err := os.ReadFile(filepath.Join(dir, ".env"))
+	require.NoError(t, err)
+	require.Contains(t, string(data), `ATLASSIAN_URL="https://acme.atlassian.net"`)
+	require.Contains(t, string(data), `ATLASSIAN_USER_TOKEN="new-token"`)
+}
+
+func TestKeyringMissing(t *testing.T) {
+	tcs := []struct {
+		name   string
+		goos   string
+		code   int
+		out    string
+		expect bool
+	}{
+		{
+			name:   "secret-tool no entry",
+			goos:   "linux",
+			code:   1,
+			expect: true,
+		},
+		{
+			name: "secret-tool failure with output",
+			goos: "linux",
+			code: 1,
+			out:  "secret-tool: Cannot autolaunch D-Bus without X11 $DISPLAY\n",
+		},
+		{
+			name: "secret-tool other exit code",
+			goos: "linux",
+			code: 2,
+		},
+		{
+			name:   "security item not found",
+			goos:   "darwin",
+			code:   44,
+			expect: true,
+		},
+		{
+			name: "security locked keychain",
+			goos: "darwin",
+			code: 36,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expect, keyringMissing(tc.goos, tc.code, []byte(tc.out)))
+		})
+	}
+}
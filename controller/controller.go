@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"context"
 	"log"
 	"os"
 	"os/signal"
@@ -9,6 +10,7 @@ import (
 	"syscall"
 	"time"
 	"tui/services"
+	"tui/utils"
 
 	"github.com/mattn/go-tty"
 
@@ -27,20 +29,48 @@ const (
 	LoadingData string = "loading_data"
 	ReloadData  string = "reload_data"
 	ErrorFetch  string = "error_fetch"
+	CyclePeriod string = "cycle_period"
+
+	OpenOverlay   string = "open_overlay"
+	CloseOverlay  string = "close_overlay"
+	ApplyFilter   string = "apply_filter"
+	SwitchProfile string = "switch_profile"
+)
+
+// keys read by overlays owning the tty, outside of valid rune range
+const (
+	keyUp   rune = -1
+	keyDown rune = -2
+	keyEsc  rune = -3
+)
+
+// profile switcher overlay take the widget slot after guide, project
+// picker come after it but is reached with [4], then jql filter overlay
+const (
+	switcherWidget = 6
+	projectWidget  = 7
+	filterWidget   = 8
 )
 
 type ControllerChild map[int]chan<- string
 
 type Controller struct {
 	ActiveWidget      int
+	prevWidget        int
 	controllersChild  ControllerChild
 	GlobalChan        chan interface{}
 	handler           termhandler.TermhandlerType
 	wg                *sync.WaitGroup
 	service           services.ServiceType
 	channelIsFetching map[int]bool
-	getSelectedName   func() string
-	getDates          func() (int, int)
+	fetchMutex        sync.Mutex
+	fetchSeq          int
+	cancelFetch       context.CancelFunc
+	cancelReload      context.CancelFunc
+	getAccountId      func() string
+	getRange          func() utils.PeriodRange
+	getCursorProject  func() string
+	switched          <-chan SwitchResult
 }
 
 func NewController(
@@ -49,8 +79,10 @@ func NewController(
 	service *services.ServiceType,
 	ctrlChild ControllerChild,
 	globalChan chan interface{},
-	getSelectedName func() string,
-	getDates func() (int, int),
+	getAccountId func() string,
+	getRange func() utils.PeriodRange,
+	getCursorProject func() string,
+	switched <-chan SwitchResult,
 ) ControllerType {
 	return &Controller{
 		wg:                wg,
@@ -60,8 +92,10 @@ func NewController(
 		GlobalChan:        globalChan,
 		ActiveWidget:      0,
 		channelIsFetching: map[int]bool{},
-		getSelectedName:   getSelectedName,
-		getDates:          getDates,
+		getAccountId:      getAccountId,
+		getRange:          getRange,
+		getCursorProject:  getCursorProject,
+		switched:          switched,
 	}
 }
 
@@ -80,7 +114,14 @@ func (c *Controller) listenExit() {
 
 func (c *Controller) listenRelistenKeyPress() {
 	go func() {
-		for range c.GlobalChan {
+		for msg := range c.GlobalChan {
+			switch msg {
+			case CloseOverlay:
+				c.closeFilter(false)
+			case ApplyFilter:
+				c.closeFilter(true)
+			}
+
 			c.ListenKeyPress()
 		}
 	}()
@@ -105,6 +146,7 @@ func (c *Controller) SetupController() {
 	c.listenExit()
 	c.listenResize()
 	c.listenRelistenKeyPress()
+	c.fetchDefaultUser()
 	c.ListenKeyPress()
 }
 
@@ -132,7 +174,7 @@ func (c *Controller) ListenKeyPress() {
 
 		switch char {
 		case '1', '2', '3':
-			if c.ActiveWidget == 3 {
+			if c.ActiveWidget == 3 || c.ActiveWidget == switcherWidget {
 				continue
 			}
 
@@ -155,6 +197,28 @@ func (c *Controller) ListenKeyPress() {
 					cchild <- charString
 				}()
 			}
+		case '4':
+			if c.ActiveWidget == 3 || c.ActiveWidget == switcherWidget {
+				continue
+			}
+
+			if isFetching, ok := c.channelIsFetching[c.ActiveWidget]; ok && isFetching {
+				continue
+			}
+
+			guideW, _ := c.controllersChild[5]
+			guideW <- strconv.Itoa(projectWidget + 1)
+			c.ActiveWidget = projectWidget
+			for i, cchild := range c.controllersChild {
+				// guide read "4" as detail log guide
+				if i == 5 {
+					continue
+				}
+
+				go func() {
+					cchild <- "4"
+				}()
+			}
 		case 'A', 'k':
 			if isFetching, ok := c.channelIsFetching[c.ActiveWidget]; ok && isFetching {
 				continue
@@ -191,60 +255,58 @@ func (c *Controller) ListenKeyPress() {
 				continue
 			}
 
-			c.channelIsFetching[c.ActiveWidget] = true
-
-			go func(aw int) {
-				childChan <- LoadingData
-
-				switch aw {
-				case 0:
-					c.service.FetchMembers()
-					c.service.FetchUsers()
-				case 2:
-					month, year := c.getDates()
-					name := c.getSelectedName()
-					c.service.FetchIssues(services.FetchWorklogPayload{
-						Year:  year,
-						Month: month,
-						Name:  name,
-					})
-				}
+			if c.ActiveWidget == 2 {
+				c.fetchIssues(c.payload(c.getAccountId()))
+				continue
+			}
 
-				childChan <- ReloadData
-				c.channelIsFetching[aw] = false
-			}(c.ActiveWidget)
+			c.reloadUsers(nil)
+		case 'm':
+			if c.ActiveWidget != 1 {
+				continue
+			}
+
+			if isFetching, ok := c.channelIsFetching[c.ActiveWidget]; ok && isFetching {
+				continue
+			}
+
+			childChan <- CyclePeriod
 		case 'q':
 			c.exitApp()
+		case 'p':
+			if c.ActiveWidget == 3 {
+				continue
+			}
+
+			c.toggleSwitcher()
+		case 27: // handle Esc, arrow keys also start with Esc so skip when sequence follow
+			if tty.Buffered() {
+				continue
+			}
+
+			if c.ActiveWidget == switcherWidget {
+				c.toggleSwitcher()
+				continue
+			}
+
+			c.cancelFetches()
 		case 13: // handle Enter
+			if c.ActiveWidget == switcherWidget {
+				c.switchProfile()
+				continue
+			}
+
+			if c.ActiveWidget == projectWidget {
+				c.toggleProject()
+				continue
+			}
+
 			if c.ActiveWidget == 0 || c.ActiveWidget == 1 {
 				if isFetching, ok := c.channelIsFetching[c.ActiveWidget]; ok && isFetching {
 					continue
 				}
 
-				month, year := c.getDates()
-				name := c.getSelectedName()
-				c.channelIsFetching[c.ActiveWidget] = true
-
-				go func(aw int) {
-					wdChan, _ := c.controllersChild[2]
-					dashChan, _ := c.controllersChild[4]
-					wdChan <- LoadingData
-
-					err := c.service.FetchIssues(services.FetchWorklogPayload{
-						Year:  year,
-						Month: month,
-						Name:  name,
-					})
-					if err != nil {
-						c.channelIsFetching[aw] = false
-						wdChan <- ErrorFetch
-						return
-					}
-
-					wdChan <- ReloadData
-					dashChan <- ReloadData
-					c.channelIsFetching[aw] = false
-				}(c.ActiveWidget)
+				c.fetchIssues(c.payload(c.getAccountId()))
 				continue
 			}
 
@@ -271,11 +333,247 @@ func (c *Controller) ListenKeyPress() {
 				tty.Close()
 				return
 			}
+		case 'f':
+			if c.ActiveWidget == 3 || c.ActiveWidget == switcherWidget {
+				continue
+			}
+
+			// filter overlay read the tty until it is closed
+			c.openFilter()
+			tty.Close()
+			return
 		}
 		time.Sleep(10 * time.Millisecond)
 	}
 }
 
+// fetchIssues cancel worklog fetch still in flight and start new one, only
+// the latest fetch report back to worklog and dashboard widgets
+func (c *Controller) fetchIssues(payload services.FetchWorklogPayload) {
+	wdChan, _ := c.controllersChild[2]
+	dashChan, _ := c.controllersChild[4]
+
+	c.fetchMutex.Lock()
+	isLoading := c.cancelFetch != nil
+	if isLoading {
+		c.cancelFetch()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancelFetch = cancel
+	c.fetchSeq++
+	seq := c.fetchSeq
+	c.fetchMutex.Unlock()
+
+	// loading indicator still running when replacing previous fetch
+	if !isLoading {
+		wdChan <- LoadingData
+	}
+
+	go func() {
+		defer cancel()
+
+		err := c.service.FetchIssues(ctx, payload)
+
+		c.fetchMutex.Lock()
+		if seq != c.fetchSeq {
+			// replaced by newer fetch, let it report
+			c.fetchMutex.Unlock()
+			return
+		}
+		c.cancelFetch = nil
+		c.fetchMutex.Unlock()
+
+		switch {
+		case ctx.Err() != nil: // cancelled by Esc, previous data is kept
+			wdChan <- ReloadData
+		case err != nil:
+			wdChan <- ErrorFetch
+		default:
+			wdChan <- ReloadData
+			dashChan <- ReloadData
+		}
+	}()
+}
+
+// payload build fetch of accountId for chosen period, projects and filter
+func (c *Controller) payload(accountId string) services.FetchWorklogPayload {
+	period := c.getRange()
+
+	return services.FetchWorklogPayload{
+		AccountId: accountId,
+		Projects:  c.service.GetSelectedProjects(),
+		Filter:    c.service.GetFilter(),
+		From:      period.From,
+		To:        period.To,
+		Label:     period.Label(),
+	}
+}
+
+// fetchDefaultUser load worklogs of preselected user for the chosen period,
+// authenticated user when it is on the roster
+func (c *Controller) fetchDefaultUser() {
+	accountId := c.service.GetDefaultAccountId()
+	if accountId == "" {
+		return
+	}
+
+	c.fetchIssues(c.payload(accountId))
+}
+
+// toggleProject flip project under cursor in and out of scope, then refetch
+// worklogs of selected user
+func (c *Controller) toggleProject() {
+	key := c.getCursorProject()
+	if key == "" {
+		return
+	}
+
+	c.service.ToggleProject(key)
+	projectChan, _ := c.controllersChild[projectWidget]
+	projectChan <- ReloadData
+
+	if accountId := c.getAccountId(); accountId != "" {
+		c.fetchIssues(c.payload(accountId))
+	}
+}
+
+// reloadUsers re-fetch roster into Users widget, afterReload run once the
+// new roster is in place
+func (c *Controller) reloadUsers(afterReload func()) {
+	childChan, _ := c.controllersChild[0]
+	projectChan, _ := c.controllersChild[projectWidget]
+
+	c.channelIsFetching[0] = true
+	ctx, cancel := context.WithCancel(context.Background())
+	c.fetchMutex.Lock()
+	// newer reload supersede one still running
+	if c.cancelReload != nil {
+		c.cancelReload()
+	}
+	c.cancelReload = cancel
+	c.fetchMutex.Unlock()
+
+	go func() {
+		defer cancel()
+		childChan <- LoadingData
+
+		err := c.service.FetchMyself(ctx)
+		if err == nil {
+			err = c.service.FetchMembers(ctx)
+		}
+		if err == nil {
+			err = c.service.FetchUsers(ctx)
+		}
+		if err == nil {
+			err = c.service.LoadProjects(ctx)
+		}
+
+		// cancelled reload keep previous roster
+		if err != nil && ctx.Err() == nil {
+			childChan <- ErrorFetch
+		} else {
+			childChan <- ReloadData
+			projectChan <- ReloadData
+		}
+		c.channelIsFetching[0] = false
+
+		if err == nil && afterReload != nil {
+			afterReload()
+		}
+	}()
+}
+
+// toggleSwitcher open profile switcher over the widgets, closing it redraw
+// whatever was behind
+func (c *Controller) toggleSwitcher() {
+	switcherChan, _ := c.controllersChild[switcherWidget]
+	guideW, _ := c.controllersChild[5]
+
+	if c.ActiveWidget != switcherWidget {
+		c.prevWidget = c.ActiveWidget
+		c.ActiveWidget = switcherWidget
+		switcherChan <- OpenOverlay
+		guideW <- strconv.Itoa(switcherWidget + 1)
+		return
+	}
+
+	c.ActiveWidget = c.prevWidget
+	switcherChan <- CloseOverlay
+
+	c.handler.Clear()
+	for _, cchan := range c.controllersChild {
+		cchan <- Resize
+	}
+	guideW <- strconv.Itoa(c.ActiveWidget + 1)
+}
+
+// switchProfile point service at profile picked in switcher, then reload
+// roster and worklogs of its default user
+func (c *Controller) switchProfile() {
+	switcherChan, _ := c.controllersChild[switcherWidget]
+
+	// switcher own its entries, picked one come back with its config
+	switcherChan <- SwitchProfile
+	res := <-c.switched
+	if res.Config == nil {
+		return
+	}
+
+	c.cancelFetches()
+	c.service.SetConfig(res.Config)
+	c.toggleSwitcher()
+
+	// profile may define other periods
+	dateChan, _ := c.controllersChild[1]
+	dateChan <- ReloadData
+
+	c.reloadUsers(c.fetchDefaultUser)
+}
+
+// openFilter show jql filter overlay over the widgets
+func (c *Controller) openFilter() {
+	filterChan, _ := c.controllersChild[filterWidget]
+
+	c.prevWidget = c.ActiveWidget
+	c.ActiveWidget = filterWidget
+	filterChan <- OpenOverlay
+}
+
+// closeFilter redraw widgets behind filter overlay, applied filter refetch
+// worklogs of selected user
+func (c *Controller) closeFilter(apply bool) {
+	guideW, _ := c.controllersChild[5]
+
+	c.ActiveWidget = c.prevWidget
+	c.handler.Clear()
+	for _, cchan := range c.controllersChild {
+		cchan <- Resize
+	}
+	guideW <- strconv.Itoa(c.ActiveWidget + 1)
+
+	if !apply {
+		return
+	}
+
+	if accountId := c.getAccountId(); accountId != "" {
+		c.fetchIssues(c.payload(accountId))
+	}
+}
+
+// cancelFetches cancel every fetch in flight
+func (c *Controller) cancelFetches() {
+	c.fetchMutex.Lock()
+	defer c.fetchMutex.Unlock()
+
+	if c.cancelFetch != nil {
+		c.cancelFetch()
+	}
+
+	if c.cancelReload != nil {
+		c.cancelReload()
+	}
+}
+
 // exitApp implements ControllerType.
 func (c *Controller) exitApp() {
 	c.handler.Clear()
@@ -288,3 +586,36 @@ func (c *Controller) exitApp() {
 
 	os.Exit(0)
 }
+
+// readTTYKey read single key from t, arrow up and down are reported as keyUp
+// and keyDown, plain Esc as keyEsc and other escape sequences are dropped
+func readTTYKey(t *tty.TTY) rune {
+	for {
+		char, err := t.ReadRune()
+		if err != nil {
+			panic(err)
+		}
+
+		if char != 27 {
+			return char
+		}
+
+		if !t.Buffered() {
+			return keyEsc
+		}
+
+		// arrow keys are Esc [ A/B/C/D
+		t.ReadRune()
+		arrow, err := t.ReadRune()
+		if err != nil {
+			panic(err)
+		}
+
+		switch arrow {
+		case 'A':
+			return keyUp
+		case 'B':
+			return keyDown
+		}
+	}
+}
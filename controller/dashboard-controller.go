@@ -23,10 +23,13 @@ type DashboardSummary struct {
 	TotalBacklog   int
 	TotalWorklog   int
 	TotalTimeSpent int
+	SkippedWorklog int
 	Name           string
 	Email          string
-	Month          int
-	Year           int
+	Filter         string
+	From           time.Time
+	To             time.Time
+	Label          string
 }
 
 type DashboardController struct {
@@ -74,10 +77,13 @@ func (d *DashboardController) ListenFromController() {
 					TotalBacklog:   sl.TotalBacklog,
 					TotalWorklog:   sl.TotalWorklog,
 					TotalTimeSpent: sl.TotalTimeSpent,
+					SkippedWorklog: sl.SkippedWorklog,
 					Name:           user.DisplayName,
 					Email:          user.EmailAdrres,
-					Month:          wl.Month,
-					Year:           wl.Year,
+					Filter:         wl.Filter,
+					From:           wl.From,
+					To:             wl.To,
+					Label:          wl.Label,
 				}
 
 				d.mutex.Lock()
@@ -180,27 +186,29 @@ func (d *DashboardController) renderBody() {
 	d.handler.MoveCursor(termhandler.Position{d.props.RenderPosX + 3, d.props.RenderPosY + 1})
 	d.handler.Draw(fmt.Sprintf("\033[97;1m%s\033[0m", d.summaryData.Name))
 
-	d.handler.MoveCursor(
-		termhandler.Position{d.props.RenderPosX + d.props.Width - 11, d.props.RenderPosY + 1},
-	)
-	date := time.Date(
-		d.summaryData.Year,
-		time.Month(d.summaryData.Month+1),
-		0,
-		0,
-		0,
-		0,
-		0,
-		&time.Location{},
-	)
-	month := ""
-	year := ""
-	if d.summaryData.Year > 0 {
-		year = fmt.Sprintf("%d", date.Year())
-		month = date.Format("Jan")
+	isMonth := d.isWholeMonth()
+	period := ""
+	if !d.summaryData.From.IsZero() {
+		period = fmt.Sprintf(
+			"%s - %s",
+			d.summaryData.From.Format("Jan 02"),
+			d.summaryData.To.Format("Jan 02"),
+		)
+		if d.summaryData.Label != "" {
+			period = d.summaryData.Label
+		}
+		if isMonth {
+			period = d.summaryData.From.Format("Jan 2006")
+		}
 	}
 
-	d.handler.Draw(fmt.Sprintf("\033[37;1m%s %s\033[0m", month, year))
+	d.handler.MoveCursor(
+		termhandler.Position{
+			d.props.RenderPosX + d.props.Width - 3 - len(period),
+			d.props.RenderPosY + 1,
+		},
+	)
+	d.handler.Draw(fmt.Sprintf("\033[37;1m%s\033[0m", period))
 
 	d.handler.MoveCursor(termhandler.Position{d.props.RenderPosX + 3, d.props.RenderPosY + 2})
 	email := "-"
@@ -210,6 +218,23 @@ func (d *DashboardController) renderBody() {
 
 	d.handler.Draw(fmt.Sprintf("%s", email))
 
+	if d.summaryData.Filter != "" {
+		filter := []rune(fmt.Sprintf(" │ Filter: %s", d.summaryData.Filter))
+		maxLen := d.props.Width - 10 - len([]rune(email))
+		if maxLen < 0 {
+			maxLen = 0
+		}
+		if len(filter) > maxLen {
+			// too narrow for ellipsis, cut it plain
+			if maxLen < 3 {
+				filter = filter[:maxLen]
+			} else {
+				filter = append(filter[:maxLen-3], []rune("...")...)
+			}
+		}
+		d.handler.Draw(fmt.Sprintf("\033[33m%s\033[0m", string(filter)))
+	}
+
 	d.handler.MoveCursor(termhandler.Position{d.props.RenderPosX + 3, d.props.RenderPosY + 3})
 	d.handler.Draw(fmt.Sprintf("%s", strings.Repeat("─", d.props.Width-6)))
 
@@ -223,6 +248,9 @@ func (d *DashboardController) renderBody() {
 	d.handler.Draw(
 		fmt.Sprintf(" Total Worklog       : \033[97;1m%d\033[0m", d.summaryData.TotalWorklog),
 	)
+	if d.summaryData.SkippedWorklog > 0 {
+		d.handler.Draw(fmt.Sprintf("\033[33m (%d unreadable)\033[0m", d.summaryData.SkippedWorklog))
+	}
 
 	d.handler.MoveCursor(termhandler.Position{d.props.RenderPosX + 3, d.props.RenderPosY + 6})
 	d.handler.Draw(
@@ -236,7 +264,10 @@ func (d *DashboardController) renderBody() {
 	d.handler.MoveCursor(termhandler.Position{d.props.RenderPosX + 1, d.props.RenderPosY + 8})
 	d.handler.Draw(" \033[97;1mAs of Today\033[0m")
 
-	targetMonth, targetToday := utils.GetWorkDays(d.summaryData.Month, d.summaryData.Year)
+	targetMonth, targetToday := utils.GetPeriodWorkDays(utils.PeriodRange{
+		From: d.summaryData.From,
+		To:   d.summaryData.To,
+	}, d.service.GetWorkingHours())
 
 	d.handler.MoveCursor(termhandler.Position{d.props.RenderPosX + 1, d.props.RenderPosY + 9})
 	d.handler.Draw(fmt.Sprintf(" Target: %s", utils.FormatSecondToHourMinute(targetToday, true)))
@@ -260,10 +291,16 @@ func (d *DashboardController) renderBody() {
 	)
 	d.handler.Draw("┃")
 
-	// Percentage Month
+	// Percentage Month, named period or whole range
+	periodTitle := "Range"
+	if isMonth {
+		periodTitle = "Month"
+	} else if d.summaryData.Label != "" {
+		periodTitle = "Period"
+	}
 
 	d.handler.MoveCursor(termhandler.Position{d.props.RenderPosX + 1, d.props.RenderPosY + 13})
-	d.handler.Draw(" \033[97;1mMonth\033[0m")
+	d.handler.Draw(fmt.Sprintf(" \033[97;1m%-6s\033[0m", periodTitle))
 
 	d.handler.MoveCursor(termhandler.Position{d.props.RenderPosX + 1, d.props.RenderPosY + 14})
 	d.handler.Draw(fmt.Sprintf(" Target: %s", utils.FormatSecondToHourMinute(targetMonth, true)))
@@ -286,6 +323,13 @@ func (d *DashboardController) renderBody() {
 	d.handler.Draw("┃")
 }
 
+// isWholeMonth report whether summary range is single calendar month
+func (d *DashboardController) isWholeMonth() bool {
+	from, to := utils.MonthRange(int(d.summaryData.From.Month()), d.summaryData.From.Year())
+
+	return d.summaryData.From.Equal(from) && d.summaryData.To.Equal(to)
+}
+
 func (d *DashboardController) generateRGBChart(target int) string {
 	var barPercentage float32
 	fromRGB := [3]int{255, 0, 64}
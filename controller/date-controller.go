@@ -6,25 +6,12 @@ import (
 	"strings"
 	"sync"
 	"time"
+	"tui/services"
+	"tui/utils"
 
 	termhandler "tui/term-handler"
 )
 
-var Months = [12]string{
-	"January",
-	"February",
-	"March",
-	"April",
-	"May",
-	"June",
-	"July",
-	"August",
-	"September",
-	"October",
-	"November",
-	"December",
-}
-
 type DateProps struct {
 	RenderPosX int
 	RenderPosY int
@@ -34,52 +21,65 @@ type DateProps struct {
 }
 
 type DateController struct {
-	monthCursor  int
 	dateCursor   int
-	year         int
+	periods      []utils.Period
+	periodIndex  int // len(periods) mean free range
+	current      utils.PeriodRange
+	from         time.Time
+	to           time.Time
 	widgetNumber int
 	isActive     bool
 	localChan    chan string
 	globalChan   chan interface{}
 	handler      termhandler.TermhandlerType
+	service      services.ServiceType
 	mutex        *sync.Mutex
 	props        DateProps
 }
 
 func NewDateController(
 	handler *termhandler.TermhandlerType,
+	service *services.ServiceType,
 	mutex *sync.Mutex,
 	globalChan chan interface{},
 	widgetNumber int,
 	dateProps DateProps,
 ) DateControllerType {
-	return &DateController{
-		monthCursor:  int(time.Now().Month()) - 1,
+	d := &DateController{
 		dateCursor:   0,
-		year:         time.Now().Year(),
 		localChan:    make(chan string, 2),
 		globalChan:   globalChan,
 		widgetNumber: widgetNumber,
 		isActive:     false,
 		handler:      *handler,
+		service:      *service,
 		mutex:        mutex,
 		props:        dateProps,
 	}
+	d.loadPeriods()
+
+	return d
 }
 
 func (d *DateController) GetChan() chan<- string {
 	return d.localChan
 }
 
-func (d *DateController) GetDates() (int, int) {
-	return d.monthCursor + 1, d.year
+// GetRange implements DateControllerType.
+// It return chosen period, free range has no name
+func (d *DateController) GetRange() utils.PeriodRange {
+	if d.isRange() {
+		return utils.PeriodRange{From: d.from, To: d.to}
+	}
+
+	return d.current
 }
 
 func (d *DateController) ListenFromController() {
 	go func() {
 		for resChan := range d.localChan {
 			switch resChan {
-			case "1", "2", "3":
+			case "1", "2", "3", "4":
 				charNum, err := strconv.Atoi(resChan)
 				if err != nil {
 					continue
@@ -94,15 +94,21 @@ func (d *DateController) ListenFromController() {
 				d.mutex.Lock()
 				d.reloadActiveIndicator()
 				d.mutex.Unlock()
+			case CyclePeriod:
+				d.cyclePeriod()
+
+				d.mutex.Lock()
+				d.renderBody()
+				d.mutex.Unlock()
+			case ReloadData:
+				d.loadPeriods()
+
+				d.mutex.Lock()
+				d.renderBody()
+				d.mutex.Unlock()
 			case GoUp:
-				switch d.dateCursor {
-				case 0:
-					if d.monthCursor == 0 {
-						continue
-					}
-					d.monthCursor--
-				case 1:
-					d.year--
+				if !d.step(-1) {
+					continue
 				}
 
 				d.mutex.Lock()
@@ -127,14 +133,8 @@ func (d *DateController) ListenFromController() {
 				d.renderBody()
 				d.mutex.Unlock()
 			case GoDown:
-				switch d.dateCursor {
-				case 0:
-					if d.monthCursor == 11 {
-						continue
-					}
-					d.monthCursor++
-				case 1:
-					d.year++
+				if !d.step(1) {
+					continue
 				}
 
 				d.mutex.Lock()
@@ -218,13 +218,20 @@ func (d *DateController) renderBody() {
 
 		d.handler.Draw("│")
 
+		left := d.current.Name
+		right := d.current.YearName
+		if d.isRange() {
+			left = d.from.Format(time.DateOnly)
+			right = d.to.Format(time.DateOnly)
+		}
+
 		highlight := ""
 		if d.isActive && d.dateCursor == 0 {
 			highlight = "\u001b[37;1m"
 		}
 
-		d.handler.Draw(fmt.Sprintf(" %s%s\033[0m", highlight, Months[d.monthCursor]))
-		d.handler.Draw(strings.Repeat(" ", d.props.Width/2-(len(Months[d.monthCursor]))-1))
+		d.handler.Draw(fmt.Sprintf(" %s%s\033[0m", highlight, left))
+		d.handler.Draw(strings.Repeat(" ", d.props.Width/2-len(left)-1))
 
 		d.handler.Draw("│") // separator
 
@@ -233,8 +240,8 @@ func (d *DateController) renderBody() {
 			highlight = "\u001b[37;1m"
 		}
 
-		d.handler.Draw(fmt.Sprintf(" %s%d\033[0m", highlight, d.year))
-		d.handler.Draw(strings.Repeat(" ", d.props.Width/2-6))
+		d.handler.Draw(fmt.Sprintf(" %s%s\033[0m", highlight, right))
+		d.handler.Draw(strings.Repeat(" ", d.props.Width/2-len(right)-2))
 
 		d.handler.Draw("│")
 
@@ -244,6 +251,73 @@ func (d *DateController) renderBody() {
 	}
 }
 
+// loadPeriods read periods of current profile and select occurrence of the
+// first one containing today
+func (d *DateController) loadPeriods() {
+	d.periods = d.service.GetPeriods()
+	if len(d.periods) == 0 {
+		d.periods = []utils.Period{{Name: utils.PeriodMonth, Kind: utils.PeriodMonth}}
+	}
+
+	d.periodIndex = 0
+	d.current = d.periods[0].At(time.Now())
+}
+
+func (d *DateController) isRange() bool {
+	return d.periodIndex == len(d.periods)
+}
+
+// cyclePeriod switch to next period then free range, every switch keep
+// start of previous selection
+func (d *DateController) cyclePeriod() {
+	from, to := d.current.From, d.current.To
+	if d.isRange() {
+		from, to = d.from, d.to
+	}
+
+	d.periodIndex = (d.periodIndex + 1) % (len(d.periods) + 1)
+	if d.isRange() {
+		d.from, d.to = from, to
+		return
+	}
+
+	d.current = d.periods[d.periodIndex].At(from)
+}
+
+// step move selection by n, period field step through periods and year field
+// through years of them, free range move end under cursor by days
+func (d *DateController) step(n int) bool {
+	if d.isRange() {
+		return d.moveRange(n)
+	}
+
+	period := d.periods[d.periodIndex]
+	if d.dateCursor == 1 {
+		n *= period.PerYear()
+	}
+
+	d.current = period.Shift(d.current, n)
+	return true
+}
+
+// moveRange shift end of range under cursor by days, it refuse to cross the
+// other end or grow beyond utils.GRID_DAYS
+func (d *DateController) moveRange(days int) bool {
+	from, to := d.from, d.to
+	if d.dateCursor == 0 {
+		from = from.AddDate(0, 0, days)
+	} else {
+		to = to.AddDate(0, 0, days)
+	}
+
+	if from.After(to) || to.Sub(from).Hours()/24 >= utils.GRID_DAYS {
+		return false
+	}
+
+	d.from, d.to = from, to
+	return true
+}
+
 func (d *DateController) reloadActiveIndicator() {
 	d.handler.MoveCursor(
 		termhandler.Position{
@@ -0,0 +1,180 @@
+package controller
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"tui/services"
+	"tui/utils"
+
+	"github.com/mattn/go-tty"
+
+	termhandler "tui/term-handler"
+)
+
+type ErrorPanelProps struct {
+	Width      int
+	Height     int
+	RenderPosX int
+	RenderPosY int
+	Title      *string
+}
+
+type ErrorPanelController struct {
+	handler termhandler.TermhandlerType
+	mutex   *sync.Mutex
+	props   ErrorPanelProps
+	message string
+}
+
+func NewErrorPanelController(
+	handler *termhandler.TermhandlerType,
+	mutex *sync.Mutex,
+	errorPanelProps ErrorPanelProps,
+) ErrorPanelControllerType {
+	return &ErrorPanelController{
+		handler: *handler,
+		mutex:   mutex,
+		props:   errorPanelProps,
+	}
+}
+
+// Prompt implements ErrorPanelControllerType.
+// It render error panel and block until user choose to retry (returns true)
+// or quit, quitting restore terminal before exiting
+func (e *ErrorPanelController) Prompt(err error) bool {
+	e.message = services.ErrorMessage(err)
+
+	e.mutex.Lock()
+	e.handler.Clear()
+	e.CreateWindow()
+	e.handler.Render()
+	e.mutex.Unlock()
+
+	tty, err := tty.Open()
+	if err != nil {
+		panic(err)
+	}
+	defer tty.Close()
+
+	for {
+		char, err := tty.ReadRune()
+		if err != nil {
+			panic(err)
+		}
+
+		switch char {
+		case 'r':
+			e.mutex.Lock()
+			e.handler.Clear()
+			e.handler.Render()
+			e.mutex.Unlock()
+			return true
+		case 'q', 3: // q or Ctrl+C
+			tty.Close()
+			e.exitApp()
+			return false
+		}
+	}
+}
+
+// CreateWindow implements ErrorPanelControllerType.
+func (e *ErrorPanelController) CreateWindow() {
+	e.handler.MoveCursor(
+		termhandler.Position{
+			e.props.RenderPosX,
+			e.props.RenderPosY,
+		},
+	)
+
+	for i := 0; i < e.props.Width; i++ {
+		if i == 0 {
+			e.handler.Draw("╭")
+			continue
+		}
+
+		if i == e.props.Width-1 {
+			e.handler.Draw("╮")
+			continue
+		}
+
+		if i == 3 {
+			printTitle := fmt.Sprintf(" \033[31;1m%s\033[0m ", *e.props.Title)
+			e.handler.Draw(printTitle)
+			i = i + len(*e.props.Title) + 1
+			continue
+		}
+
+		e.handler.Draw("─")
+	}
+
+	e.renderBody()
+
+	e.handler.MoveCursor(
+		termhandler.Position{
+			e.props.RenderPosX,
+			e.props.RenderPosY + e.props.Height + 1,
+		},
+	)
+
+	for i := 0; i < e.props.Width; i++ {
+		if i == 0 {
+			e.handler.Draw("╰")
+			continue
+		}
+
+		if i == e.props.Width-1 {
+			e.handler.Draw("╯")
+			continue
+		}
+
+		e.handler.Draw("─")
+	}
+
+	e.handler.MoveCursor(
+		termhandler.Position{
+			e.props.RenderPosX,
+			e.props.RenderPosY + e.props.Height + 2,
+		},
+	)
+	e.handler.Draw("\033[32;1m[r] : Retry │ [q] : Quit\033[0m")
+}
+
+func (e *ErrorPanelController) renderBody() {
+	lines := utils.FormatCommentDesc(e.message, e.props.Width-4)
+
+	for i := 0; i < e.props.Height; i++ {
+		e.handler.MoveCursor(
+			termhandler.Position{
+				e.props.RenderPosX,
+				e.props.RenderPosY + i + 1,
+			},
+		)
+		e.handler.Draw("│")
+		e.handler.Draw(strings.Repeat(" ", e.props.Width-2))
+		e.handler.Draw("│")
+
+		if i < len(lines) {
+			e.handler.MoveCursor(
+				termhandler.Position{
+					e.props.RenderPosX + 2,
+					e.props.RenderPosY + i + 1,
+				},
+			)
+			e.handler.Draw(lines[i])
+		}
+	}
+}
+
+func (e *ErrorPanelController) exitApp() {
+	e.handler.Clear()
+	e.handler.ShowCursor()
+	e.handler.MoveCursor(termhandler.Position{1, 1})
+
+	if err := e.handler.Render(); err != nil {
+		panic(err)
+	}
+
+	os.Exit(0)
+}
@@ -0,0 +1,341 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"tui/services"
+	"tui/utils"
+
+	"github.com/mattn/go-tty"
+
+	termhandler "tui/term-handler"
+)
+
+type FilterProps struct {
+	Width      int
+	Height     int
+	RenderPosX int
+	RenderPosY int
+	Title      *string
+}
+
+type FilterController struct {
+	cursor     int
+	offsite    int
+	isOpen     bool
+	onList     bool
+	input      string
+	status     string
+	lastError  error
+	filters    []services.Filter
+	localChan  chan string
+	globalChan chan interface{}
+	handler    termhandler.TermhandlerType
+	service    services.ServiceType
+	mutex      *sync.Mutex
+	props      FilterProps
+}
+
+func NewFilterController(
+	handler *termhandler.TermhandlerType,
+	service *services.ServiceType,
+	mutex *sync.Mutex,
+	globalChan chan interface{},
+	filterProps FilterProps,
+) FilterControllerType {
+	return &FilterController{
+		localChan:  make(chan string, 2),
+		globalChan: globalChan,
+		filters:    []services.Filter{},
+		handler:    *handler,
+		service:    *service,
+		mutex:      mutex,
+		props:      filterProps,
+	}
+}
+
+// GetChan implements FilterControllerType.
+func (f *FilterController) GetChan() chan<- string {
+	return f.localChan
+}
+
+// ListenFromController implements FilterControllerType.
+func (f *FilterController) ListenFromController() {
+	go func() {
+		for resChan := range f.localChan {
+			switch resChan {
+			case OpenOverlay:
+				f.handleInput()
+			case Resize:
+				if !f.isOpen {
+					continue
+				}
+
+				f.mutex.Lock()
+				f.CreateWindow()
+				f.handler.Render()
+				f.mutex.Unlock()
+			}
+		}
+	}()
+}
+
+// handleInput own the tty until filter is applied or overlay is closed,
+// then hand key press back to controller through globalChan
+func (f *FilterController) handleInput() {
+	f.isOpen = true
+	f.onList = false
+	f.cursor = 0
+	f.offsite = 0
+	f.input = f.service.GetFilter()
+	f.lastError = nil
+	f.status = "Loading favourite filters..."
+	f.filters = []services.Filter{}
+
+	f.mutex.Lock()
+	f.CreateWindow()
+	f.handler.Render()
+	f.mutex.Unlock()
+
+	filters, err := f.service.FetchFavouriteFilters(context.Background())
+	f.filters = filters
+	f.lastError = err
+	f.status = ""
+	f.render()
+
+	t, err := tty.Open()
+	if err != nil {
+		panic(err)
+	}
+	defer t.Close()
+
+	for {
+		char := readTTYKey(t)
+
+		switch char {
+		case keyEsc:
+			f.close(CloseOverlay)
+			return
+		case 9: // Tab
+			f.onList = !f.onList && len(f.filters) > 0
+		case 13: // Enter
+			if f.onList {
+				f.input = f.filters[f.cursor+f.offsite].Jql
+				f.onList = false
+			}
+
+			if f.apply() {
+				f.close(ApplyFilter)
+				return
+			}
+		case 'k', keyUp:
+			if !f.onList {
+				if char == 'k' {
+					f.input += string(char)
+				}
+				break
+			}
+
+			if f.cursor > 0 {
+				f.cursor--
+			} else if f.offsite > 0 {
+				f.offsite--
+			}
+		case 'j', keyDown:
+			if !f.onList {
+				if char == 'j' {
+					f.input += string(char)
+				}
+				break
+			}
+
+			if f.cursor+f.offsite >= len(f.filters)-1 {
+				break
+			}
+
+			if f.cursor < f.listHeight()-1 {
+				f.cursor++
+			} else {
+				f.offsite++
+			}
+		case 127:
+			if !f.onList && len(f.input) > 0 {
+				runes := []rune(f.input)
+				f.input = string(runes[:len(runes)-1])
+			}
+		default:
+			if !f.onList {
+				f.input += string(char)
+			}
+		}
+
+		f.render()
+	}
+}
+
+// apply validate input and store it as filter, empty input clear filter
+func (f *FilterController) apply() bool {
+	jql := strings.TrimSpace(f.input)
+	if jql != "" {
+		f.status = "Validating..."
+		f.render()
+
+		err := f.service.ValidateJQL(context.Background(), jql)
+		f.status = ""
+		if err != nil {
+			f.lastError = err
+			return false
+		}
+	}
+
+	f.service.SetFilter(jql)
+	return true
+}
+
+func (f *FilterController) close(msg string) {
+	f.isOpen = false
+	f.globalChan <- msg
+}
+
+func (f *FilterController) render() {
+	f.mutex.Lock()
+	f.renderBody()
+	f.mutex.Unlock()
+}
+
+func (f *FilterController) listHeight() int {
+	// input, message and header rows
+	return f.props.Height - 3
+}
+
+// CreateWindow implements FilterControllerType.
+func (f *FilterController) CreateWindow() {
+	f.handler.MoveCursor(termhandler.Position{f.props.RenderPosX, f.props.RenderPosY})
+
+	for i := 0; i < f.props.Width; i++ {
+		if i == 0 {
+			f.handler.Draw("╭")
+			continue
+		}
+
+		if i == f.props.Width-1 {
+			f.handler.Draw("╮")
+			continue
+		}
+
+		if i == 3 {
+			printTitle := fmt.Sprintf(" \033[37;1m%s\033[0m ", *f.props.Title)
+			f.handler.Draw(printTitle)
+			i = i + len(*f.props.Title) + 1
+			continue
+		}
+
+		f.handler.Draw("─")
+	}
+
+	f.renderBody()
+
+	f.handler.MoveCursor(
+		termhandler.Position{f.props.RenderPosX, f.props.RenderPosY + f.props.Height + 1},
+	)
+
+	for i := 0; i < f.props.Width; i++ {
+		if i == 0 {
+			f.handler.Draw("╰")
+			continue
+		}
+
+		if i == f.props.Width-1 {
+			f.handler.Draw("╯")
+			continue
+		}
+
+		f.handler.Draw("─")
+	}
+}
+
+// renderBody draw jql input, message row and favourite filters below
+func (f *FilterController) renderBody() {
+	hightlight := "\u001b[30;107m"
+	innerWidth := f.props.Width - 4
+
+	for i := 0; i < f.props.Height; i++ {
+		f.handler.MoveCursor(
+			termhandler.Position{f.props.RenderPosX, f.props.RenderPosY + i + 1},
+		)
+		f.handler.Draw("│")
+		f.handler.Draw(strings.Repeat(" ", f.props.Width-2))
+		f.handler.Draw("│")
+	}
+
+	// keep end of long query visible while typing
+	input := []rune(f.input + "_")
+	if f.onList {
+		input = []rune(f.input)
+	}
+	if maxLen := innerWidth - 5; len(input) > maxLen {
+		input = input[len(input)-maxLen:]
+	}
+
+	label := "JQL:"
+	if !f.onList {
+		label = "\033[97;1mJQL:\033[0m"
+	}
+	f.handler.MoveCursor(termhandler.Position{f.props.RenderPosX + 2, f.props.RenderPosY + 1})
+	f.handler.Draw(fmt.Sprintf("%s %s", label, string(input)))
+
+	f.handler.MoveCursor(termhandler.Position{f.props.RenderPosX + 2, f.props.RenderPosY + 2})
+	switch {
+	case f.status != "":
+		f.handler.Draw(fmt.Sprintf("\033[33;1m%s\033[0m", f.status))
+	case f.lastError != nil:
+		line := utils.FormatCommentDesc(services.ErrorMessage(f.lastError), innerWidth)[0]
+		f.handler.Draw(fmt.Sprintf("\x1b[31;1m%s\x1b[0m", line))
+	default:
+		f.handler.Draw("\033[90mANDed with user and date, empty clear the filter\033[0m")
+	}
+
+	f.handler.MoveCursor(termhandler.Position{f.props.RenderPosX + 2, f.props.RenderPosY + 3})
+	header := "Favourite filters"
+	if f.onList {
+		header = fmt.Sprintf("\033[97;1m%s\033[0m", header)
+	}
+	f.handler.Draw(header)
+
+	for i := 0; i < f.listHeight() && i+f.offsite < len(f.filters); i++ {
+		filter := f.filters[i+f.offsite]
+
+		name := []rune(fmt.Sprintf(" %s  ", filter.Name))
+		jql := []rune(filter.Jql)
+		if len(name) > innerWidth {
+			name = name[:innerWidth]
+		}
+		if len(name)+len(jql) > innerWidth {
+			jql = jql[:innerWidth-len(name)]
+		}
+
+		line := fmt.Sprintf("%s\033[90m%s\033[0m", string(name), string(jql))
+		if f.onList && i == f.cursor {
+			filler := strings.Repeat(" ", innerWidth-len(name)-len(jql))
+			line = hightlight + string(name) + string(jql) + filler + "\033[0m"
+		}
+
+		f.handler.MoveCursor(
+			termhandler.Position{f.props.RenderPosX + 1, f.props.RenderPosY + i + 4},
+		)
+		f.handler.Draw(line)
+	}
+
+	guide := "[Enter] : Apply │ [Tab] : Favourites │ [Esc] : Close"
+	if f.onList {
+		guide = "[k][j] / [][] : Up Down │ [Enter] : Apply │ [Tab] : Edit JQL │ [Esc] : Close"
+	}
+	f.handler.MoveCursor(
+		termhandler.Position{f.props.RenderPosX, f.props.RenderPosY + f.props.Height + 2},
+	)
+	f.handler.Draw(fmt.Sprintf("\033[32;1m%s\033[0m%s", guide, strings.Repeat(" ", 20)))
+
+	f.handler.Render()
+}
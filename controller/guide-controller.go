@@ -35,10 +35,12 @@ func NewGuideController(
 		props:       guideProps,
 		activeGuide: 0,
 		guideOptions: map[int]string{
-			0: "[k][j] / [][] : Up Down │ [i] : Search │ [Enter] : Interact │ [q] : Quit",
-			1: "[k][j][h][l] / [][][][] : Up Down Left Right │ [Enter] : Interact │ [q] : Quit",
-			2: "[k][j][h][l] / [][][][] : Up Down Left Right │ [Enter] : Interact │ [q] : Quit",
+			0: "[k][j] / [][] : Up Down │ [i] : Search │ [Enter] : Interact │ [r] : Reload │ [f] : Filter │ [p] : Profiles │ [q] : Quit",
+			1: "[k][j][h][l] / [][][][] : Up Down Left Right │ [Enter] : Interact │ [m] : Period / Range │ [f] : Filter │ [p] : Profiles │ [q] : Quit",
+			2: "[k][j][h][l] / [][][][] : Up Down Left Right │ [Enter] : Interact │ [r] : Reload │ [f] : Filter │ [p] : Profiles │ [q] : Quit",
 			3: "[k][j] / [][] : Up Down │ [Enter] : Back │ [q] : Quit",
+			6: "[k][j] / [][] : Up Down │ [Enter] : Switch │ [Esc] / [p] : Close │ [q] : Quit",
+			7: "[k][j] / [][] : Up Down │ [Enter] : Toggle project │ [f] : Filter │ [p] : Profiles │ [q] : Quit",
 		},
 	}
 }
@@ -53,7 +55,7 @@ func (g *GuideController) ListenFromController() {
 	go func() {
 		for resChan := range g.localChan {
 			switch resChan {
-			case "1", "2", "3", "4":
+			case "1", "2", "3", "4", "7", "8":
 				aw, err := strconv.Atoi(resChan)
 				if err != nil {
 					continue
@@ -103,8 +105,8 @@ func (g *GuideController) renderGuide() {
 
 	guideText, _ := g.guideOptions[g.activeGuide]
 	widgetsOptionsText := ""
-	if g.activeGuide != 3 {
-		widgetsOptionsText = "[1][2][3] : Change Widget │ "
+	if g.activeGuide != 3 && g.activeGuide != 6 {
+		widgetsOptionsText = "[1][2][3][4] : Change Widget │ "
 	}
 	g.handler.Draw(fmt.Sprintf("\033[32;1m%s%s\033[0m", widgetsOptionsText, guideText))
 	g.handler.Render()
@@ -118,6 +120,6 @@ func (g *GuideController) cleanBody() {
 		},
 	)
 
-	g.handler.Draw(fmt.Sprintf("%s", strings.Repeat(" ", 100)))
+	g.handler.Draw(fmt.Sprintf("%s", strings.Repeat(" ", 170)))
 	g.handler.Render()
 }
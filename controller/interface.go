@@ -1,5 +1,11 @@
 package controller
 
+import (
+	"tui/config"
+	"tui/services"
+	"tui/utils"
+)
+
 type GuideControllerType interface {
 	GetChan() chan<- string
 	CreateWindow()
@@ -12,9 +18,12 @@ type WindowControllerType interface {
 	GetActiveCursor() int
 	GetOffsite() int
 	GetChan() chan<- string
-	GetSelectedName() string
+	GetSelectedAccountId() string
+	setItems([]services.UserItem, string)
+	selectAccountId(string)
 	CreateWindow()
 	renderReload()
+	renderError(string)
 	cleanBody()
 	renderList()
 	renderBodyList(*string, int, string)
@@ -30,23 +39,60 @@ type ControllerType interface {
 	listenExit()
 	listenResize()
 	listenRelistenKeyPress()
+	fetchIssues(services.FetchWorklogPayload)
+	payload(string) services.FetchWorklogPayload
+	fetchDefaultUser()
+	toggleProject()
+	openFilter()
+	closeFilter(bool)
+	reloadUsers(afterReload func())
+	toggleSwitcher()
+	switchProfile()
+	cancelFetches()
 	exitApp()
 }
 
 type DateControllerType interface {
 	GetChan() chan<- string
-	GetDates() (int, int)
+	GetRange() utils.PeriodRange
+	CreateWindow()
+	ListenFromController()
+	renderBody()
+	loadPeriods()
+	isRange() bool
+	cyclePeriod()
+	step(int) bool
+	moveRange(int) bool
+	reloadActiveIndicator()
+}
+
+type ProjectControllerType interface {
+	GetChan() chan<- string
+	GetCursorProject() string
 	CreateWindow()
 	ListenFromController()
 	renderBody()
 	reloadActiveIndicator()
 }
 
+type FilterControllerType interface {
+	GetChan() chan<- string
+	ListenFromController()
+	handleInput()
+	apply() bool
+	close(string)
+	render()
+	listHeight() int
+	CreateWindow()
+	renderBody()
+}
+
 type DashboardControllerType interface {
 	GetChan() chan<- string
 	CreateWindow()
 	cleanBody()
 	renderBody()
+	isWholeMonth() bool
 	generateRGBChart(int) string
 	ListenFromController()
 }
@@ -72,3 +118,34 @@ type WorklogDescControllerType interface {
 	cleanBody()
 	ListenFromController()
 }
+
+type ErrorPanelControllerType interface {
+	Prompt(error) bool
+	CreateWindow()
+	renderBody()
+	exitApp()
+}
+
+type SetupWizardControllerType interface {
+	Run(string, error) config.JiraConfigType
+	CreateWindow()
+	storeToken(int, string, string, string, *config.Profile) error
+	tokenPassphrase(string) (string, error)
+	promptText(string, string, bool) string
+	promptSelect(string, []string) int
+	readKey() rune
+	setStatus(string)
+	render()
+	listHeight() int
+	renderBody()
+	exitApp()
+}
+
+type SwitcherControllerType interface {
+	GetChan() chan<- string
+	GetSwitchedChan() <-chan SwitchResult
+	loadSelected() SwitchResult
+	ListenFromController()
+	CreateWindow()
+	renderBody()
+}
@@ -0,0 +1,252 @@
+package controller
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"tui/services"
+
+	termhandler "tui/term-handler"
+)
+
+type ProjectProps struct {
+	RenderPosX int
+	RenderPosY int
+	Width      int
+	Height     int
+	Title      *string
+}
+
+type ProjectController struct {
+	cursor       int
+	offsite      int
+	widgetNumber int
+	isActive     bool
+	items        []string
+	localChan    chan string
+	handler      termhandler.TermhandlerType
+	service      services.ServiceType
+	mutex        *sync.Mutex
+	props        ProjectProps
+}
+
+func NewProjectController(
+	handler *termhandler.TermhandlerType,
+	service *services.ServiceType,
+	mutex *sync.Mutex,
+	widgetNumber int,
+	projectProps ProjectProps,
+) ProjectControllerType {
+	return &ProjectController{
+		localChan:    make(chan string, 2),
+		widgetNumber: widgetNumber,
+		items:        []string{},
+		handler:      *handler,
+		service:      *service,
+		mutex:        mutex,
+		props:        projectProps,
+	}
+}
+
+// GetChan implements ProjectControllerType.
+func (p *ProjectController) GetChan() chan<- string {
+	return p.localChan
+}
+
+// GetCursorProject implements ProjectControllerType.
+// Selection itself live in service so fetch read it right after toggle
+func (p *ProjectController) GetCursorProject() string {
+	if len(p.items) == 0 {
+		return ""
+	}
+
+	return p.items[p.cursor+p.offsite]
+}
+
+// ListenFromController implements ProjectControllerType.
+func (p *ProjectController) ListenFromController() {
+	p.items = p.service.GetProjects()
+
+	go func() {
+		for resChan := range p.localChan {
+			switch resChan {
+			case "1", "2", "3", "4":
+				charNum, err := strconv.Atoi(resChan)
+				if err != nil {
+					continue
+				}
+
+				p.isActive = p.widgetNumber == (charNum - 1)
+
+				p.mutex.Lock()
+				p.reloadActiveIndicator()
+				p.mutex.Unlock()
+			case GoUp:
+				if p.cursor == 0 && p.offsite == 0 {
+					continue
+				}
+
+				if p.cursor == 0 {
+					p.offsite--
+				} else {
+					p.cursor--
+				}
+
+				p.mutex.Lock()
+				p.renderBody()
+				p.mutex.Unlock()
+			case GoDown:
+				if p.cursor+p.offsite >= len(p.items)-1 {
+					continue
+				}
+
+				if p.cursor >= p.props.Height-1 {
+					p.offsite++
+				} else {
+					p.cursor++
+				}
+
+				p.mutex.Lock()
+				p.renderBody()
+				p.mutex.Unlock()
+			case ReloadData:
+				// list change only on profile switch, selection on toggle
+				items := p.service.GetProjects()
+				if len(items) != len(p.items) || strings.Join(items, ",") != strings.Join(p.items, ",") {
+					p.items = items
+					p.cursor = 0
+					p.offsite = 0
+				}
+
+				p.mutex.Lock()
+				p.CreateWindow()
+				p.handler.Render()
+				p.mutex.Unlock()
+			case Resize:
+				p.mutex.Lock()
+				p.CreateWindow()
+				p.handler.Render()
+				p.mutex.Unlock()
+			}
+		}
+	}()
+}
+
+// CreateWindow implements ProjectControllerType.
+func (p *ProjectController) CreateWindow() {
+	title := *p.props.Title
+	if len(p.service.GetSelectedProjects()) == 0 {
+		title += " (all)"
+	}
+
+	// render top border
+	p.handler.MoveCursor(termhandler.Position{p.props.RenderPosX, p.props.RenderPosY})
+	for i := 0; i < p.props.Width; i++ {
+		if i == 0 {
+			p.handler.Draw("╭")
+			continue
+		}
+
+		if i == p.props.Width-1 {
+			p.handler.Draw("╮")
+			continue
+		}
+
+		if i == 3 {
+			p.handler.Draw(fmt.Sprintf(" \u001b[37;1m%s\033[0m ", title))
+			i = i + len(title) + 1
+			continue
+		}
+
+		p.handler.Draw("─")
+	}
+
+	p.renderBody()
+
+	// render bottom border
+	p.handler.MoveCursor(
+		termhandler.Position{p.props.RenderPosX, p.props.RenderPosY + p.props.Height + 1},
+	)
+	for i := 0; i < p.props.Width; i++ {
+		if i == 0 {
+			p.handler.Draw("╰")
+			continue
+		}
+
+		if i == p.props.Width-4 {
+			highlight := ""
+			if p.isActive {
+				highlight = "\u001b[32;1m"
+			}
+			p.handler.Draw(fmt.Sprintf(" %s%d\033[0m ", highlight, p.widgetNumber+1))
+			i = p.props.Width - 2
+		} else {
+			p.handler.Draw("─")
+		}
+
+		if i == p.props.Width-1 {
+			p.handler.Draw("╯")
+		}
+	}
+}
+
+func (p *ProjectController) renderBody() {
+	selected := map[string]bool{}
+	for _, key := range p.service.GetSelectedProjects() {
+		selected[key] = true
+	}
+
+	for i := 0; i < p.props.Height; i++ {
+		p.handler.MoveCursor(
+			termhandler.Position{p.props.RenderPosX, p.props.RenderPosY + i + 1},
+		)
+		p.handler.Draw("│")
+
+		item := ""
+		if i+p.offsite < len(p.items) {
+			key := p.items[i+p.offsite]
+			mark := "[ ]"
+			if selected[key] {
+				mark = "[x]"
+			}
+
+			item = fmt.Sprintf(" %s %s", mark, key)
+			if len(item) > p.props.Width-2 {
+				item = item[:p.props.Width-2]
+			}
+		} else if i == 0 && len(p.items) == 0 {
+			item = " No project"
+		}
+
+		filler := strings.Repeat(" ", p.props.Width-2-len(item))
+		if p.isActive && i == p.cursor && i+p.offsite < len(p.items) {
+			item = fmt.Sprintf("\u001b[30;107m%s%s\033[0m", item, filler)
+			filler = ""
+		}
+		p.handler.Draw(item + filler)
+		p.handler.Draw("│")
+	}
+
+	if err := p.handler.Render(); err != nil {
+		panic(err)
+	}
+}
+
+func (p *ProjectController) reloadActiveIndicator() {
+	p.handler.MoveCursor(
+		termhandler.Position{
+			p.props.RenderPosX + p.props.Width - 3,
+			p.props.RenderPosY + p.props.Height + 1,
+		},
+	)
+
+	highlight := ""
+	if p.isActive {
+		highlight = "\u001b[32;1m"
+	}
+	p.handler.Draw(fmt.Sprintf("%s%d\033[0m", highlight, p.widgetNumber+1))
+
+	// cursor highlight only show while widget is active
+	p.renderBody()
+}
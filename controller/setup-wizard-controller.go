@@ -0,0 +1,516 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"tui/config"
+	"tui/services"
+	"tui/utils"
+
+	"github.com/mattn/go-tty"
+
+	termhandler "tui/term-handler"
+)
+
+const (
+	tokenStoreKeyring = iota
+	tokenStoreFile
+	tokenStoreEnv
+)
+
+type SetupWizardProps struct {
+	Width      int
+	Height     int
+	RenderPosX int
+	RenderPosY int
+	Title      *string
+}
+
+type SetupWizardController struct {
+	handler termhandler.TermhandlerType
+	mutex   *sync.Mutex
+	props   SetupWizardProps
+	tty     *tty.TTY
+	notice  string
+	answers []string
+	prompt  string
+	input   string
+	secret  bool
+	items   []string
+	cursor  int
+	offsite int
+	status  string
+	errMsg  string
+
+	passphrase string
+}
+
+func NewSetupWizardController(
+	handler *termhandler.TermhandlerType,
+	mutex *sync.Mutex,
+	setupWizardProps SetupWizardProps,
+) SetupWizardControllerType {
+	return &SetupWizardController{
+		handler: *handler,
+		mutex:   mutex,
+		props:   setupWizardProps,
+		answers: []string{},
+		items:   []string{},
+	}
+}
+
+// Run implements SetupWizardControllerType.
+// It ask for credentials, check them against jira, let user pick org, team
+// and project then save them as profile. Existing profile is updated unless
+// user choose to overwrite it. Return config of saved profile, Esc quit the
+// app
+func (w *SetupWizardController) Run(profile string, reason error) config.JiraConfigType {
+	file, err := config.LoadProfiles()
+	if err != nil {
+		w.errMsg = err.Error()
+	}
+
+	if profile == "" && file != nil {
+		profile = file.DefaultProfile
+	}
+	if profile == "" {
+		profile = "default"
+	}
+
+	existing, found := config.Profile{}, false
+	if file != nil {
+		existing, found = file.Profiles[profile]
+	}
+
+	if reason != nil {
+		w.notice = fmt.Sprintf("Config is incomplete (%v), let's set up profile %q.", reason, profile)
+	}
+
+	t, err := tty.Open()
+	if err != nil {
+		panic(err)
+	}
+	defer t.Close()
+	w.tty = t
+
+	w.mutex.Lock()
+	w.handler.Clear()
+	w.CreateWindow()
+	w.handler.Render()
+	w.mutex.Unlock()
+
+	ctx := context.Background()
+
+	if found {
+		overwrite := w.promptSelect(fmt.Sprintf("Profile %q already exists", profile), []string{
+			"Update it, keep teams, periods and other settings",
+			"Overwrite it",
+		})
+		if overwrite == 1 {
+			existing = config.Profile{}
+		}
+	}
+
+	// credentials, repeated until /myself accept them
+	site, email, token := "https://", existing.Auth.Email, ""
+	if existing.Site != "" {
+		site = existing.Site
+	}
+	var service services.ServiceType
+	for {
+		site = strings.TrimSuffix(w.promptText("Site URL", site, false), "/")
+		email = w.promptText("Email", email, false)
+		token = w.promptText("API token", "", true)
+
+		cfg := config.NewSetupConfig(site, email, token)
+		service = services.NewService(new(sync.WaitGroup), w.mutex, &w.handler, &cfg)
+
+		w.setStatus("Checking credentials...")
+		if err := service.FetchMyself(ctx); err != nil {
+			w.errMsg = services.ErrorMessage(err)
+			continue
+		}
+		break
+	}
+
+	w.setStatus("")
+	w.answers = append(w.answers,
+		fmt.Sprintf("Site    : %s", site),
+		fmt.Sprintf("Login   : %s <%s>", service.GetMyself().DisplayName, email),
+	)
+
+	w.setStatus("Looking up organization...")
+	orgId, err := service.FetchOrgID(ctx)
+	w.setStatus("")
+	if err != nil {
+		w.errMsg = fmt.Sprintf("could not look up organization (%s), enter it manually", services.ErrorMessage(err))
+		orgId = w.promptText("Organization ID", "", false)
+	}
+	w.answers = append(w.answers, fmt.Sprintf("Org     : %s", orgId))
+
+	w.setStatus("Fetching teams...")
+	teams, err := service.FetchTeams(ctx, orgId)
+	w.setStatus("")
+	teamId := ""
+	if err != nil || len(teams) == 0 {
+		if err != nil {
+			w.errMsg = fmt.Sprintf("could not list teams (%s), enter team id manually", services.ErrorMessage(err))
+		}
+		teamId = w.promptText("Team ID", "", false)
+		w.answers = append(w.answers, fmt.Sprintf("Team    : %s", teamId))
+	} else {
+		names := []string{}
+		for _, team := range teams {
+			names = append(names, team.DisplayName)
+		}
+
+		i := w.promptSelect("Team", names)
+		teamId = teams[i].TeamId
+		w.answers = append(w.answers, fmt.Sprintf("Team    : %s", teams[i].DisplayName))
+	}
+
+	w.setStatus("Fetching projects...")
+	projects, err := service.FetchProjects(ctx)
+	w.setStatus("")
+	project := ""
+	if err != nil || len(projects) == 0 {
+		if err != nil {
+			w.errMsg = fmt.Sprintf("could not list projects (%s), enter project key manually", services.ErrorMessage(err))
+		}
+		project = w.promptText("Project key", "", false)
+	} else {
+		names := []string{}
+		for _, p := range projects {
+			names = append(names, fmt.Sprintf("%s - %s", p.Key, p.Name))
+		}
+
+		project = projects[w.promptSelect("Project", names)].Key
+	}
+	w.answers = append(w.answers, fmt.Sprintf("Project : %s", project))
+
+	newProfile := config.Profile{
+		Site:     site,
+		Auth:     config.ProfileAuth{Email: email},
+		Org:      orgId,
+		Team:     teamId,
+		Projects: []string{project},
+	}
+
+	makeDefault := false
+	if file != nil && file.DefaultProfile != "" && file.DefaultProfile != profile {
+		makeDefault = w.promptSelect(fmt.Sprintf("Make %q the default profile", profile), []string{
+			fmt.Sprintf("No, keep %q", file.DefaultProfile),
+			"Yes",
+		}) == 1
+	}
+
+	// token never goes into profile file
+	var cfg config.JiraConfigType
+	for {
+		store := w.promptSelect("Store token in", []string{
+			"System keyring",
+			"Encrypted file",
+			".env file (plaintext)",
+		})
+
+		err := w.storeToken(store, profile, site, token, &newProfile)
+		if err == nil {
+			err = config.SaveProfile(profile, existing.WithSetup(newProfile), makeDefault)
+		}
+		if err == nil {
+			cfg, err = config.LoadConfig(profile, w.tokenPassphrase)
+		}
+		if err != nil {
+			w.errMsg = err.Error()
+			continue
+		}
+		break
+	}
+
+	w.mutex.Lock()
+	w.handler.Clear()
+	w.handler.Render()
+	w.mutex.Unlock()
+
+	return cfg
+}
+
+func (w *SetupWizardController) storeToken(
+	store int,
+	profile string,
+	site string,
+	token string,
+	newProfile *config.Profile,
+) error {
+	switch store {
+	case tokenStoreKeyring:
+		newProfile.Auth.TokenSource = config.TokenSourceKeyring
+		return config.StoreTokenKeyring(site, token)
+	case tokenStoreFile:
+		passphrase := w.promptText("Passphrase", "", true)
+		if passphrase == "" {
+			return fmt.Errorf("passphrase is empty")
+		}
+
+		cfgPath, err := config.ConfigFilePath()
+		if err != nil {
+			return err
+		}
+		path := filepath.Join(filepath.Dir(cfgPath), profile+".token")
+
+		newProfile.Auth.TokenSource = config.TokenSourceFile
+		newProfile.Auth.TokenFile = path
+
+		// config is reloaded right after, don't prompt for it again
+		w.passphrase = passphrase
+		return config.StoreTokenFile(path, token, passphrase)
+	}
+
+	newProfile.Auth.TokenSource = config.TokenSourceEnv
+	return config.StoreTokenEnv(token)
+}
+
+// tokenPassphrase unlock token file stored by the wizard
+func (w *SetupWizardController) tokenPassphrase(path string) (string, error) {
+	return w.passphrase, nil
+}
+
+// promptText read single line, secret input is masked
+func (w *SetupWizardController) promptText(label string, value string, secret bool) string {
+	w.prompt = label
+	w.input = value
+	w.secret = secret
+	w.items = []string{}
+	w.render()
+
+	for {
+		char := w.readKey()
+
+		switch char {
+		case 13: // Enter
+			if strings.TrimSpace(w.input) == "" {
+				continue
+			}
+
+			w.errMsg = ""
+			w.prompt = ""
+			return strings.TrimSpace(w.input)
+		case 127:
+			if len(w.input) > 0 {
+				runes := []rune(w.input)
+				w.input = string(runes[:len(runes)-1])
+			}
+		case keyUp, keyDown, 9:
+			continue
+		default:
+			w.input += string(char)
+		}
+
+		w.render()
+	}
+}
+
+// promptSelect let user pick one of items, return its index
+func (w *SetupWizardController) promptSelect(label string, items []string) int {
+	w.prompt = label
+	w.input = ""
+	w.items = items
+	w.cursor = 0
+	w.offsite = 0
+	w.render()
+
+	listHeight := w.listHeight()
+	for {
+		switch w.readKey() {
+		case 13: // Enter
+			w.errMsg = ""
+			w.prompt = ""
+			w.items = []string{}
+			return w.cursor + w.offsite
+		case 'k', keyUp:
+			if w.cursor > 0 {
+				w.cursor--
+			} else if w.offsite > 0 {
+				w.offsite--
+			}
+		case 'j', keyDown:
+			if w.cursor+w.offsite >= len(items)-1 {
+				continue
+			}
+
+			if w.cursor < listHeight-1 {
+				w.cursor++
+			} else {
+				w.offsite++
+			}
+		}
+
+		w.render()
+	}
+}
+
+// readKey return key typed, plain Esc or Ctrl+C quit the app
+func (w *SetupWizardController) readKey() rune {
+	char := readTTYKey(w.tty)
+	if char == 3 || char == keyEsc {
+		w.exitApp()
+	}
+
+	return char
+}
+
+func (w *SetupWizardController) setStatus(status string) {
+	w.status = status
+	w.render()
+}
+
+func (w *SetupWizardController) render() {
+	w.mutex.Lock()
+	w.renderBody()
+	w.handler.Render()
+	w.mutex.Unlock()
+}
+
+func (w *SetupWizardController) listHeight() int {
+	// rows left after notice, answers, prompt and error line
+	height := w.props.Height - len(w.answers) - 5
+	if height < 1 {
+		height = 1
+	}
+
+	return height
+}
+
+// CreateWindow implements SetupWizardControllerType.
+func (w *SetupWizardController) CreateWindow() {
+	w.handler.MoveCursor(termhandler.Position{w.props.RenderPosX, w.props.RenderPosY})
+
+	for i := 0; i < w.props.Width; i++ {
+		if i == 0 {
+			w.handler.Draw("╭")
+			continue
+		}
+
+		if i == w.props.Width-1 {
+			w.handler.Draw("╮")
+			continue
+		}
+
+		if i == 3 {
+			printTitle := fmt.Sprintf(" \033[37;1m%s\033[0m ", *w.props.Title)
+			w.handler.Draw(printTitle)
+			i = i + len(*w.props.Title) + 1
+			continue
+		}
+
+		w.handler.Draw("─")
+	}
+
+	w.renderBody()
+
+	w.handler.MoveCursor(
+		termhandler.Position{w.props.RenderPosX, w.props.RenderPosY + w.props.Height + 1},
+	)
+
+	for i := 0; i < w.props.Width; i++ {
+		if i == 0 {
+			w.handler.Draw("╰")
+			continue
+		}
+
+		if i == w.props.Width-1 {
+			w.handler.Draw("╯")
+			continue
+		}
+
+		w.handler.Draw("─")
+	}
+}
+
+func (w *SetupWizardController) renderBody() {
+	lines := []string{}
+
+	notice := "Welcome! Let's connect to your Jira site."
+	if w.notice != "" {
+		notice = w.notice
+	}
+	lines = append(lines, utils.FormatCommentDesc(notice, w.props.Width-4)...)
+	lines = append(lines, "")
+	lines = append(lines, w.answers...)
+
+	if w.status != "" {
+		lines = append(lines, "", fmt.Sprintf("\033[33;1m%s\033[0m", w.status))
+	} else if w.prompt != "" && len(w.items) == 0 {
+		input := w.input
+		if w.secret {
+			input = strings.Repeat("*", len([]rune(input)))
+		}
+		lines = append(lines, "", fmt.Sprintf("\033[97;1m%s\033[0m: %s_", w.prompt, input))
+	} else if w.prompt != "" {
+		lines = append(lines, "", fmt.Sprintf("\033[97;1m%s\033[0m:", w.prompt))
+
+		for i := 0; i < w.listHeight() && i+w.offsite < len(w.items); i++ {
+			item := w.items[i+w.offsite]
+			if len(item) > w.props.Width-6 {
+				item = item[:w.props.Width-6]
+			}
+
+			if i == w.cursor {
+				item = fmt.Sprintf("\u001b[30;107m %s \033[0m", item)
+			} else {
+				item = fmt.Sprintf(" %s ", item)
+			}
+			lines = append(lines, item)
+		}
+	}
+
+	for i := 0; i < w.props.Height; i++ {
+		w.handler.MoveCursor(
+			termhandler.Position{w.props.RenderPosX, w.props.RenderPosY + i + 1},
+		)
+		w.handler.Draw("│")
+		w.handler.Draw(strings.Repeat(" ", w.props.Width-2))
+		w.handler.Draw("│")
+
+		if i < len(lines) {
+			w.handler.MoveCursor(
+				termhandler.Position{w.props.RenderPosX + 2, w.props.RenderPosY + i + 1},
+			)
+			w.handler.Draw(lines[i])
+		}
+	}
+
+	if w.errMsg != "" {
+		line := utils.FormatCommentDesc(w.errMsg, w.props.Width-4)[0]
+		w.handler.MoveCursor(
+			termhandler.Position{w.props.RenderPosX + 2, w.props.RenderPosY + w.props.Height},
+		)
+		w.handler.Draw(fmt.Sprintf("\x1b[31;1m%s\x1b[0m", line))
+	}
+
+	guide := "[Enter] : Confirm │ [Esc] : Quit"
+	if len(w.items) > 0 {
+		guide = "[k][j] / [][] : Up Down │ [Enter] : Select │ [Esc] : Quit"
+	}
+	w.handler.MoveCursor(
+		termhandler.Position{w.props.RenderPosX, w.props.RenderPosY + w.props.Height + 2},
+	)
+	w.handler.Draw(fmt.Sprintf("\033[32;1m%s\033[0m%s", guide, strings.Repeat(" ", 20)))
+}
+
+func (w *SetupWizardController) exitApp() {
+	w.tty.Close()
+	w.handler.Clear()
+	w.handler.ShowCursor()
+	w.handler.MoveCursor(termhandler.Position{1, 1})
+
+	if err := w.handler.Render(); err != nil {
+		panic(err)
+	}
+
+	os.Exit(0)
+}
@@ -0,0 +1,271 @@
+package controller
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"tui/config"
+	"tui/utils"
+
+	termhandler "tui/term-handler"
+)
+
+type SwitcherProps struct {
+	Width         int
+	Height        int
+	RenderPosX    int
+	RenderPosY    int
+	ActiveProfile string
+	Title         *string
+}
+
+// SwitchResult answer SwitchProfile with entry picked in switcher, Config is
+// nil when it failed to load
+type SwitchResult struct {
+	Entry  config.SwitchEntry
+	Config config.JiraConfigType
+}
+
+type SwitcherController struct {
+	cursor       int
+	offsite      int
+	isOpen       bool
+	active       config.SwitchEntry
+	entries      []config.SwitchEntry
+	lastError    error
+	localChan    chan string
+	switchedChan chan SwitchResult
+	handler      termhandler.TermhandlerType
+	mutex        *sync.Mutex
+	props        SwitcherProps
+}
+
+func NewSwitcherController(
+	handler *termhandler.TermhandlerType,
+	mutex *sync.Mutex,
+	switcherProps SwitcherProps,
+) SwitcherControllerType {
+	return &SwitcherController{
+		localChan:    make(chan string, 2),
+		switchedChan: make(chan SwitchResult, 1),
+		active:       config.SwitchEntry{Label: switcherProps.ActiveProfile, Profile: switcherProps.ActiveProfile},
+		entries:      []config.SwitchEntry{},
+		handler:      *handler,
+		mutex:        mutex,
+		props:        switcherProps,
+	}
+}
+
+// GetChan implements SwitcherControllerType.
+func (s *SwitcherController) GetChan() chan<- string {
+	return s.localChan
+}
+
+// GetSwitchedChan implements SwitcherControllerType.
+// Every SwitchProfile is answered on it
+func (s *SwitcherController) GetSwitchedChan() <-chan SwitchResult {
+	return s.switchedChan
+}
+
+// loadSelected implements SwitcherControllerType.
+// It load config of entry under cursor, failure is kept to be rendered
+func (s *SwitcherController) loadSelected() SwitchResult {
+	if len(s.entries) == 0 {
+		s.lastError = fmt.Errorf("no profile to switch to")
+		return SwitchResult{}
+	}
+
+	entry := s.entries[s.cursor+s.offsite]
+	cfg, err := config.LoadConfig(entry.Profile, lockedPassphrase)
+	if err != nil {
+		s.lastError = err
+		return SwitchResult{Entry: entry}
+	}
+
+	if entry.TeamID != "" {
+		cfg = cfg.WithTeam(entry.TeamID)
+	}
+
+	s.active = entry
+	s.lastError = nil
+	return SwitchResult{Entry: entry, Config: cfg}
+}
+
+// lockedPassphrase refuse to prompt, terminal is in raw mode while tui run
+func lockedPassphrase(path string) (string, error) {
+	return "", fmt.Errorf("%s need passphrase, restart with -profile to unlock it or run setup to store token elsewhere", path)
+}
+
+// ListenFromController implements SwitcherControllerType.
+func (s *SwitcherController) ListenFromController() {
+	go func() {
+		for resChan := range s.localChan {
+			switch resChan {
+			case OpenOverlay:
+				entries, err := config.SwitchEntries()
+				s.entries = entries
+				s.lastError = err
+				s.cursor = 0
+				s.offsite = 0
+				s.isOpen = true
+
+				s.mutex.Lock()
+				s.CreateWindow()
+				s.handler.Render()
+				s.mutex.Unlock()
+			case CloseOverlay:
+				s.isOpen = false
+			case GoUp:
+				if s.cursor == 0 && s.offsite == 0 {
+					continue
+				}
+
+				if s.cursor == 0 {
+					s.offsite--
+				} else {
+					s.cursor--
+				}
+
+				s.mutex.Lock()
+				s.renderBody()
+				s.mutex.Unlock()
+			case GoDown:
+				if s.cursor+s.offsite >= len(s.entries)-1 {
+					continue
+				}
+
+				if s.cursor >= s.props.Height-2 {
+					s.offsite++
+				} else {
+					s.cursor++
+				}
+
+				s.mutex.Lock()
+				s.renderBody()
+				s.mutex.Unlock()
+			case SwitchProfile:
+				res := s.loadSelected()
+				if res.Config == nil {
+					s.mutex.Lock()
+					s.renderBody()
+					s.mutex.Unlock()
+				}
+
+				s.switchedChan <- res
+			case Resize:
+				if !s.isOpen {
+					continue
+				}
+
+				s.mutex.Lock()
+				s.CreateWindow()
+				s.handler.Render()
+				s.mutex.Unlock()
+			}
+		}
+	}()
+}
+
+// CreateWindow implements SwitcherControllerType.
+func (s *SwitcherController) CreateWindow() {
+	s.handler.MoveCursor(termhandler.Position{s.props.RenderPosX, s.props.RenderPosY})
+
+	for i := 0; i < s.props.Width; i++ {
+		if i == 0 {
+			s.handler.Draw("╭")
+			continue
+		}
+
+		if i == s.props.Width-1 {
+			s.handler.Draw("╮")
+			continue
+		}
+
+		if i == 3 {
+			printTitle := fmt.Sprintf(" \033[37;1m%s\033[0m ", *s.props.Title)
+			s.handler.Draw(printTitle)
+			i = i + len(*s.props.Title) + 1
+			continue
+		}
+
+		s.handler.Draw("─")
+	}
+
+	s.renderBody()
+
+	s.handler.MoveCursor(
+		termhandler.Position{s.props.RenderPosX, s.props.RenderPosY + s.props.Height + 1},
+	)
+
+	for i := 0; i < s.props.Width; i++ {
+		if i == 0 {
+			s.handler.Draw("╰")
+			continue
+		}
+
+		if i == s.props.Width-1 {
+			s.handler.Draw("╯")
+			continue
+		}
+
+		s.handler.Draw("─")
+	}
+}
+
+// renderBody draw entries, the last row is kept for error of latest switch
+func (s *SwitcherController) renderBody() {
+	hightlight := "\u001b[30;107m"
+	listHeight := s.props.Height - 1
+
+	for i := 0; i < s.props.Height; i++ {
+		s.handler.MoveCursor(
+			termhandler.Position{s.props.RenderPosX, s.props.RenderPosY + i + 1},
+		)
+		s.handler.Draw("│")
+		s.handler.Draw(strings.Repeat(" ", s.props.Width-2))
+		s.handler.Draw("│")
+	}
+
+	if len(s.entries) == 0 && s.lastError == nil {
+		s.handler.MoveCursor(
+			termhandler.Position{s.props.RenderPosX + 2, s.props.RenderPosY + 1},
+		)
+		path, _ := config.ConfigFilePath()
+		s.handler.Draw(fmt.Sprintf("No profiles, add them to %s", path))
+	}
+
+	for i := 0; i < listHeight && i+s.offsite < len(s.entries); i++ {
+		entry := s.entries[i+s.offsite]
+
+		marker := "  "
+		if entry == s.active {
+			marker = "● "
+		}
+
+		// labels may hold multibyte names, cut by rune
+		label := []rune(fmt.Sprintf(" %s%s", marker, entry.Label))
+		if len(label) > s.props.Width-3 {
+			label = label[:s.props.Width-3]
+		}
+		item := string(label) + strings.Repeat(" ", s.props.Width-2-len(label))
+
+		if i == s.cursor {
+			item = hightlight + item + "\033[0m"
+		}
+
+		s.handler.MoveCursor(
+			termhandler.Position{s.props.RenderPosX + 1, s.props.RenderPosY + i + 1},
+		)
+		s.handler.Draw(item)
+	}
+
+	if s.lastError != nil {
+		line := utils.FormatCommentDesc(s.lastError.Error(), s.props.Width-4)[0]
+		s.handler.MoveCursor(
+			termhandler.Position{s.props.RenderPosX + 2, s.props.RenderPosY + s.props.Height},
+		)
+		s.handler.Draw(fmt.Sprintf("\x1b[31;1m%s\x1b[0m", line))
+	}
+
+	s.handler.Render()
+}
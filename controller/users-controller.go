@@ -31,6 +31,7 @@ type UserController struct {
 	globalChan   chan interface{}
 	loadingChan  chan struct{}
 	Items        []string
+	accountIds   []string
 	windowProps  WindowProps
 	handler      termhandler.TermhandlerType
 	service      services.ServiceType
@@ -55,6 +56,7 @@ func NewUserController(
 		widgetNumber: widgetNumeber,
 		windowProps:  windowProps,
 		Items:        []string{},
+		accountIds:   []string{},
 		handler:      *handler,
 		service:      *service,
 		mutex:        mutext,
@@ -73,23 +75,63 @@ func (c *UserController) GetChan() chan<- string {
 	return c.localChan
 }
 
-func (c *UserController) GetSelectedName() string {
-	name := ""
-	if len(c.Items) > 0 {
-		name = c.Items[c.ActiveCursor+c.Offsite]
+// GetSelectedAccountId return account id of user under cursor, names are
+// only rendered since two users may share one
+func (c *UserController) GetSelectedAccountId() string {
+	accountId := ""
+	if len(c.accountIds) > 0 {
+		accountId = c.accountIds[c.ActiveCursor+c.Offsite]
+	}
+	return accountId
+}
+
+// setItems put users into the list, keeping only names containing filter
+func (c *UserController) setItems(users []services.UserItem, filter string) {
+	items := []string{}
+	accountIds := []string{}
+	for _, user := range users {
+		if !strings.Contains(strings.ToLower(user.DisplayName), strings.ToLower(filter)) {
+			continue
+		}
+
+		items = append(items, user.DisplayName)
+		accountIds = append(accountIds, user.AccountId)
+	}
+
+	c.Items = items
+	c.accountIds = accountIds
+}
+
+// selectAccountId move cursor onto user, scrolling list when it is below
+// the window, cursor stay on top when user is not listed
+func (c *UserController) selectAccountId(accountId string) {
+	c.ActiveCursor = 0
+	c.Offsite = 0
+
+	for i, item := range c.accountIds {
+		if item != accountId {
+			continue
+		}
+
+		if i < c.windowProps.WindowHeight {
+			c.ActiveCursor = i
+		} else {
+			c.ActiveCursor = c.windowProps.WindowHeight - 1
+			c.Offsite = i - c.ActiveCursor
+		}
+		return
 	}
-	return name
 }
 
 func (c *UserController) ListenFromController() {
 	// getting data from service
-	usersName := c.service.GetUsersName()
-	c.Items = usersName
+	c.setItems(c.service.GetUserItems(), "")
+	c.selectAccountId(c.service.GetDefaultAccountId())
 
 	go func() {
 		for resChan := range c.localChan {
 			switch resChan {
-			case "1", "2", "3":
+			case "1", "2", "3", "4":
 				charNum, err := strconv.Atoi(resChan)
 				if err != nil {
 					continue
@@ -139,7 +181,7 @@ func (c *UserController) ListenFromController() {
 				go c.renderList()
 				c.mutex.Unlock()
 			case LoadingData:
-				c.Items = []string{}
+				c.setItems([]services.UserItem{}, "")
 				c.Offsite = 0
 				c.ActiveCursor = 0
 
@@ -147,11 +189,18 @@ func (c *UserController) ListenFromController() {
 				c.cleanBody()
 				c.renderReload()
 				c.mutex.Unlock()
+			case ErrorFetch:
+				c.loadingChan <- struct{}{}
+
+				c.mutex.Lock()
+				c.cleanBody()
+				c.renderError(fmt.Sprintf("%s, press [r] to retry", services.ErrorMessage(c.service.GetLastError())))
+				c.mutex.Unlock()
 			case ReloadData:
 				c.loadingChan <- struct{}{}
 
-				usersName := c.service.GetUsersName()
-				c.Items = usersName
+				c.setItems(c.service.GetUserItems(), "")
+				c.selectAccountId(c.service.GetDefaultAccountId())
 				c.mutex.Lock()
 				go c.renderList()
 				c.mutex.Unlock()
@@ -219,7 +268,7 @@ func (c *UserController) handleSearch() {
 			c.ActiveCursor = 0
 			c.Offsite = 0
 
-			c.Items = utils.FilterStrings(c.service.GetUsersName(), input)
+			c.setItems(c.service.GetUserItems(), input)
 			c.cleanBody()
 			c.renderList()
 
@@ -434,6 +483,25 @@ func (c *UserController) reloadActiveIndicator() {
 	c.handler.Render()
 }
 
+func (c *UserController) renderError(msg string) {
+	lines := utils.FormatCommentDesc(msg, c.windowProps.WindowWidth-2)
+	for i, line := range lines {
+		if i >= c.windowProps.WindowHeight {
+			break
+		}
+
+		c.handler.MoveCursor(
+			termhandler.Position{
+				c.windowProps.RenderPosX + 1,
+				c.windowProps.RenderPosY + i + 3,
+			},
+		)
+		c.handler.Draw(fmt.Sprintf("\x1b[31;1m%s\x1b[0m", line))
+	}
+
+	c.handler.Render()
+}
+
 func (c *UserController) renderReload() {
 	go func() {
 		loading := []string{"|", "/", "-", "\\"}
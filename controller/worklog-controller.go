@@ -110,7 +110,7 @@ func (w *WorklogController) ListenFromController() {
 				w.reloadActiveDateIndicator()
 				w.mutex.Unlock()
 
-				w.ReloadWLDesc(w.dateCursor)
+				w.ReloadWLDesc(w.dateCursor + 1)
 			case GoDown:
 				if (w.dateCursor+7 > len(w.worklogData)-1) || w.isLoading {
 					continue
@@ -128,7 +128,7 @@ func (w *WorklogController) ListenFromController() {
 				w.reloadActiveDateIndicator()
 				w.mutex.Unlock()
 
-				w.ReloadWLDesc(w.dateCursor)
+				w.ReloadWLDesc(w.dateCursor + 1)
 			case GoLeft:
 				if w.dateCursor == 0 || w.isLoading {
 					continue
@@ -153,9 +153,9 @@ func (w *WorklogController) ListenFromController() {
 				w.reloadActiveDateIndicator()
 				w.mutex.Unlock()
 
-				w.ReloadWLDesc(w.dateCursor)
+				w.ReloadWLDesc(w.dateCursor + 1)
 			case GoRight:
-				if (w.dateCursor == len(w.worklogData)-1) || w.isLoading {
+				if (w.dateCursor >= len(w.worklogData)-1) || w.isLoading {
 					continue
 				}
 
@@ -178,7 +178,7 @@ func (w *WorklogController) ListenFromController() {
 				w.reloadActiveDateIndicator()
 				w.mutex.Unlock()
 
-				w.ReloadWLDesc(w.dateCursor)
+				w.ReloadWLDesc(w.dateCursor + 1)
 			case LoadingData:
 				w.dateCursor = 0
 				w.dateCursorTrack = DateCursorTrack{
@@ -193,7 +193,7 @@ func (w *WorklogController) ListenFromController() {
 				w.isLoading = false
 
 				w.mutex.Lock()
-				w.ReloadWLDesc(w.dateCursor)
+				w.ReloadWLDesc(w.dateCursor + 1)
 				w.mapWorklogData()
 				w.renderBody()
 				w.mutex.Unlock()
@@ -211,16 +211,19 @@ func (w *WorklogController) ListenFromController() {
 				w.handler.Draw("             ")
 				w.handler.Render()
 
-				w.handler.MoveCursor(
-					termhandler.Position{
-						w.props.RenderPosX + 1,
-						w.props.RenderPosY + 2,
-					},
-				)
-				w.handler.Draw("\x1b[31;1m failed\x1b[0m")
+				errMsg := fmt.Sprintf(" failed: %s", services.ErrorMessage(w.service.GetLastError()))
+				for i, line := range utils.FormatCommentDesc(errMsg, w.props.Width-4) {
+					w.handler.MoveCursor(
+						termhandler.Position{
+							w.props.RenderPosX + 1,
+							w.props.RenderPosY + 2 + i,
+						},
+					)
+					w.handler.Draw(fmt.Sprintf("\x1b[31;1m%s\x1b[0m", line))
+				}
 				w.handler.Render()
 				w.mutex.Unlock()
-			case "1", "2", "3":
+			case "1", "2", "3", "4":
 				charNum, err := strconv.Atoi(resChan)
 				if err != nil {
 					continue
@@ -279,20 +282,11 @@ func (w *WorklogController) mapWorklogData() {
 	w.worklogData = []WorklogData{} // reset data
 	wlData := w.service.GetWorklogs()
 
-	for i := 0; i < wlData.LastDate+1; i++ {
-		date := fmt.Sprintf("%d", i)
-		month := fmt.Sprintf("%d", wlData.Month)
-
-		if i < 10 {
-			date = fmt.Sprintf("0%d", i)
-		}
-
-		if wlData.Month < 10 {
-			month = fmt.Sprintf("0%d", wlData.Month)
-		}
+	// cell i is day i+1 of range
+	for i := 1; i <= wlData.LastDate && i <= utils.GRID_DAYS; i++ {
+		parsed := wlData.From.AddDate(0, 0, i-1)
+		date := fmt.Sprintf("%02d", parsed.Day())
 
-		fDate := fmt.Sprintf("%d-%s-%s", wlData.Year, month, date)
-		parsed, _ := time.Parse(time.DateOnly, fDate)
 		w.worklogData = append(w.worklogData, WorklogData{
 			date: date,
 			day:  parsed.Weekday().String(),
@@ -352,9 +346,7 @@ func (w *WorklogController) renderBody() {
 						}
 
 						date = fmt.Sprintf("%s%s\033[0m", highlight, w.worklogData[dateIndex].date)
-						if !(i == 0 && k == 0) {
-							day = fmt.Sprintf("%s", w.worklogData[dateIndex].day)
-						}
+						day = fmt.Sprintf("%s", w.worklogData[dateIndex].day)
 					}
 
 					remSpace := 12 - len(day)
@@ -368,7 +360,7 @@ func (w *WorklogController) renderBody() {
 					continue
 				}
 
-				if !(i == 0 && k == 0) && j == 4 {
+				if j == 4 {
 					todayTime := ""
 					todayTimeSpent := "  "
 					if dateIndex < len(w.worklogData) {
@@ -416,12 +408,14 @@ func (w *WorklogController) renderBody() {
 	}
 }
 
+// thresholds follow working hours policy, full day, 3/4 and half of it
 func (w *WorklogController) calculateTimespentHighlight(n int) string {
-	if n > 28800 {
+	fullDay := w.service.GetWorkingHours() * 3600
+	if n > fullDay {
 		return "36"
-	} else if n > 21600 {
+	} else if n > fullDay*3/4 {
 		return "32"
-	} else if n > 14400 {
+	} else if n > fullDay/2 {
 		return "33"
 	}
 	return "31"
@@ -465,12 +459,7 @@ func (w *WorklogController) reloadActiveDateIndicator() {
 		},
 	)
 
-	dateBeforeNum := fmt.Sprintf("%d", w.dateCursorBefore)
-	if w.dateCursorBefore < 10 {
-		dateBeforeNum = fmt.Sprintf("0%d", w.dateCursorBefore)
-	}
-
-	w.handler.Draw(dateBeforeNum)
+	w.handler.Draw(w.worklogData[w.dateCursorBefore].date)
 
 	w.handler.MoveCursor(
 		termhandler.Position{
@@ -481,12 +470,7 @@ func (w *WorklogController) reloadActiveDateIndicator() {
 
 	highlight := "\033[37;44;1;3m"
 
-	dateNum := fmt.Sprintf("%d", w.dateCursor)
-	if w.dateCursor < 10 {
-		dateNum = fmt.Sprintf("0%d", w.dateCursor)
-	}
-
-	w.handler.Draw(fmt.Sprintf("%s%s\033[0m", highlight, dateNum))
+	w.handler.Draw(fmt.Sprintf("%s%s\033[0m", highlight, w.worklogData[w.dateCursor].date))
 
 	w.handler.Render()
 }
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"tui/config"
+	"tui/services"
+
+	termhandler "tui/term-handler"
+)
+
+// runDoctorCommand check config and every jira endpoint used by the tui,
+// exit non zero when any check fails
+func runDoctorCommand(profile string) {
+	cfg, err := config.LoadConfig(profile, config.PromptPassphrase)
+	if err != nil {
+		fmt.Printf("\033[31;1mFAIL\033[0m config\n     %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("\033[32;1mPASS\033[0m %-14s %s (%s, %s)\n", "config", cfg.GetAtlassianURL(), cfg.GetDeployment(), cfg.GetAuthMode())
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	var wg sync.WaitGroup
+	var mutex sync.Mutex
+	thandler := termhandler.NewTermHandler()
+	service := services.NewService(&wg, &mutex, &thandler, &cfg)
+
+	failed := false
+	for _, check := range service.Diagnose(ctx) {
+		switch {
+		case check.Skipped:
+			fmt.Printf("\033[33;1mSKIP\033[0m %-14s\n     %s\n", check.Name, check.Hint)
+		case check.Err != nil:
+			failed = true
+			fmt.Printf("\033[31;1mFAIL\033[0m %-14s %s\n     %v\n     hint: %s\n", check.Name, statusText(check.Status), check.Err, check.Hint)
+		default:
+			fmt.Printf("\033[32;1mPASS\033[0m %-14s %s\n", check.Name, statusText(check.Status))
+		}
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+func statusText(status int) string {
+	if status == 0 {
+		return "no response"
+	}
+
+	return fmt.Sprintf("HTTP %d", status)
+}
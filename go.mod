@@ -6,7 +6,9 @@ require (
 	github.com/joho/godotenv v1.5.1
 	github.com/mattn/go-tty v0.0.7
 	github.com/stretchr/testify v1.9.0
+	golang.org/x/crypto v0.28.0
 	golang.org/x/term v0.25.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -14,5 +16,4 @@ require (
 	github.com/mattn/go-isatty v0.0.20 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	golang.org/x/sys v0.26.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -0,0 +1,11 @@
+package jql
+
+type QueryType interface {
+	Project(...string) QueryType
+	WorklogAuthor(string) QueryType
+	WorklogDate(string, string) QueryType
+	Custom(string) QueryType
+	OrderBy(string, bool) QueryType
+	Err() error
+	String() string
+}
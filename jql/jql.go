@@ -0,0 +1,189 @@
+// Package jql compose jira queries, every identifier and value put into the
+// query is quoted so it can't change the query around it
+package jql
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// ErrUnbalanced is reported for user jql whose parentheses don't pair up, it
+// could close parentheses wrapping it and escape the other clauses
+var ErrUnbalanced = errors.New("unbalanced parentheses")
+
+var (
+	// bare field names, custom fields may be referenced as cf[10010]
+	identRegexp = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_]*|cf\[[0-9]+\])$`)
+
+	// saved filters usually end with their own ordering, which can't be ANDed
+	orderByRegexp = regexp.MustCompile(`(?i)^order\s+by\b`)
+
+	quoteReplacer = strings.NewReplacer(
+		`\`, `\\`,
+		`"`, `\"`,
+		"\n", `\n`,
+		"\r", `\r`,
+		"\t", `\t`,
+	)
+)
+
+type Query struct {
+	clauses []string
+	orderBy []string
+	err     error
+}
+
+func New() QueryType {
+	return &Query{
+		clauses: []string{},
+		orderBy: []string{},
+	}
+}
+
+// Quote return s as jql string literal
+func Quote(s string) string {
+	return fmt.Sprintf(`"%s"`, quoteReplacer.Replace(s))
+}
+
+// Field return field name as is when it is plain identifier, quoted
+// otherwise (names with spaces or jql operators)
+func Field(name string) string {
+	if identRegexp.MatchString(name) {
+		return name
+	}
+
+	return Quote(name)
+}
+
+// Project implements QueryType.
+// No key mean no project clause, every visible project is searched
+func (q *Query) Project(keys ...string) QueryType {
+	if len(keys) == 0 {
+		return q
+	}
+
+	values := []string{}
+	for _, key := range keys {
+		values = append(values, Quote(key))
+	}
+
+	q.clauses = append(q.clauses, fmt.Sprintf("project IN (%s)", strings.Join(values, ", ")))
+	return q
+}
+
+// WorklogAuthor implements QueryType.
+// It take accountId on cloud and username on data center
+func (q *Query) WorklogAuthor(id string) QueryType {
+	q.clauses = append(q.clauses, fmt.Sprintf("worklogAuthor = %s", Quote(id)))
+	return q
+}
+
+// WorklogDate implements QueryType.
+// Both dates are inclusive and formatted as yyyy-mm-dd
+func (q *Query) WorklogDate(from string, to string) QueryType {
+	q.clauses = append(
+		q.clauses,
+		fmt.Sprintf("worklogDate >= %s", Quote(from)),
+		fmt.Sprintf("worklogDate <= %s", Quote(to)),
+	)
+	return q
+}
+
+// StripOrderBy drop ORDER BY ending jql, text inside string literals or
+// parentheses is never taken as one
+func StripOrderBy(jql string) string {
+	end, _ := scan(jql)
+	return strings.TrimSpace(jql[:end])
+}
+
+// scan walk jql skipping string literals, it return where top-level ORDER BY
+// start (len of jql when there is none) and whether parentheses before it
+// pair up without closing more than was opened
+func scan(jql string) (int, bool) {
+	quote := rune(0)
+	escaped := false
+	depth := 0
+	balanced := true
+	prev := ' '
+
+	for i, r := range jql {
+		switch {
+		case escaped:
+			escaped = false
+		case quote != 0 && r == '\\':
+			escaped = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+			if depth < 0 {
+				balanced = false
+			}
+		case depth == 0 && !isIdentRune(prev) && orderByRegexp.MatchString(jql[i:]):
+			return i, balanced
+		}
+		prev = r
+	}
+
+	return len(jql), balanced && depth == 0
+}
+
+func isIdentRune(r rune) bool {
+	return r == '_' || r == '.' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// Custom implements QueryType.
+// User written jql is wrapped so its OR can't leak into other clauses, its
+// ORDER BY is dropped. Jql with unbalanced parentheses is left out and
+// reported by Err
+func (q *Query) Custom(jql string) QueryType {
+	end, balanced := scan(jql)
+	if !balanced {
+		q.err = fmt.Errorf("%w in %q", ErrUnbalanced, jql)
+		return q
+	}
+
+	jql = strings.TrimSpace(jql[:end])
+	if jql == "" {
+		return q
+	}
+
+	q.clauses = append(q.clauses, fmt.Sprintf("(%s)", jql))
+	return q
+}
+
+// OrderBy implements QueryType.
+func (q *Query) OrderBy(field string, desc bool) QueryType {
+	direction := "ASC"
+	if desc {
+		direction = "DESC"
+	}
+
+	q.orderBy = append(q.orderBy, fmt.Sprintf("%s %s", Field(field), direction))
+	return q
+}
+
+// Err implements QueryType.
+func (q *Query) Err() error {
+	return q.err
+}
+
+// String implements QueryType.
+func (q *Query) String() string {
+	res := strings.Join(q.clauses, " AND ")
+
+	if len(q.orderBy) > 0 {
+		res = strings.TrimSpace(fmt.Sprintf("%s ORDER BY %s", res, strings.Join(q.orderBy, ", ")))
+	}
+
+	return res
+}
@@ -0,0 +1,211 @@
+package jql
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuote(t *testing.T) {
+	tcs := []struct {
+		name   string
+		value  string
+		expect string
+	}{
+		{name: "plain", value: "PROJ", expect: `"PROJ"`},
+		{name: "double quote", value: `say "hi"`, expect: `"say \"hi\""`},
+		{name: "backslash", value: `a\b`, expect: `"a\\b"`},
+		{name: "escaped quote stay escaped", value: `x\" OR project = Y`, expect: `"x\\\" OR project = Y"`},
+		{name: "line break", value: "a\nb", expect: `"a\nb"`},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expect, Quote(tc.value))
+		})
+	}
+}
+
+func TestField(t *testing.T) {
+	tcs := []struct {
+		name   string
+		field  string
+		expect string
+	}{
+		{name: "identifier", field: "worklogDate", expect: "worklogDate"},
+		{name: "custom field id", field: "cf[10010]", expect: "cf[10010]"},
+		{name: "name with space", field: "Story Points", expect: `"Story Points"`},
+		{name: "name with operator", field: "created DESC, key", expect: `"created DESC, key"`},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expect, Field(tc.field))
+		})
+	}
+}
+
+func TestQuery(t *testing.T) {
+	tcs := []struct {
+		name      string
+		query     QueryType
+		expect    string
+		expectErr error
+	}{
+		{
+			name:   "empty",
+			query:  New(),
+			expect: "",
+		},
+		{
+			name:   "only ordering",
+			query:  New().OrderBy("updated", true),
+			expect: "ORDER BY updated DESC",
+		},
+		{
+			name: "worklog of user in month",
+			query: New().
+				Project("PROJ", "OPS").
+				WorklogAuthor("acc-1").
+				WorklogDate("2024-03-01", "2024-03-31").
+				OrderBy("created", true),
+			expect: `project IN ("PROJ", "OPS") AND worklogAuthor = "acc-1" AND worklogDate >= "2024-03-01" AND worklogDate <= "2024-03-31" ORDER BY created DESC`,
+		},
+		{
+			name:   "no project",
+			query:  New().Project().WorklogAuthor("acc-1"),
+			expect: `worklogAuthor = "acc-1"`,
+		},
+		{
+			name:   "project key with quote",
+			query:  New().Project(`P") OR (project = "X`),
+			expect: `project IN ("P\") OR (project = \"X")`,
+		},
+		{
+			name:   "custom wrap or",
+			query:  New().Custom("labels = a OR labels = b").WorklogAuthor("acc-1"),
+			expect: `(labels = a OR labels = b) AND worklogAuthor = "acc-1"`,
+		},
+		{
+			name:   "custom drop ordering",
+			query:  New().Custom("component = API order by created DESC").OrderBy("created", false),
+			expect: "(component = API) ORDER BY created ASC",
+		},
+		{
+			name:   "custom with only ordering",
+			query:  New().Custom("ORDER BY rank").WorklogAuthor("acc-1"),
+			expect: `worklogAuthor = "acc-1"`,
+		},
+		{
+			name:   "custom keep order by inside string",
+			query:  New().Custom(`summary ~ "order by phone" OR text ~ 'Order By mail'`),
+			expect: `(summary ~ "order by phone" OR text ~ 'Order By mail')`,
+		},
+		{
+			name:   "custom keep order by after escaped quote",
+			query:  New().Custom(`summary ~ "say \"hi\" order by phone" order by rank`),
+			expect: `(summary ~ "say \"hi\" order by phone")`,
+		},
+		{
+			name:   "custom drop ordering on quoted field",
+			query:  New().Custom(`labels = x ORDER BY "Story Points" ASC`),
+			expect: "(labels = x)",
+		},
+		{
+			name:   "custom keep balanced parentheses",
+			query:  New().Custom("(a = 1 OR b = 2) AND c in (3, 4)").WorklogAuthor("acc-1"),
+			expect: `((a = 1 OR b = 2) AND c in (3, 4)) AND worklogAuthor = "acc-1"`,
+		},
+		{
+			name:   "custom ignore parentheses inside string",
+			query:  New().Custom(`summary ~ "a) OR (b"`).WorklogAuthor("acc-1"),
+			expect: `(summary ~ "a) OR (b") AND worklogAuthor = "acc-1"`,
+		},
+		{
+			name:      "custom reject closing paren escaping wrapper",
+			query:     New().Custom("a = 1) OR (b = 2").WorklogAuthor("acc-1"),
+			expect:    `worklogAuthor = "acc-1"`,
+			expectErr: ErrUnbalanced,
+		},
+		{
+			name:      "custom reject unclosed paren",
+			query:     New().Custom("(a = 1 OR b = 2").WorklogAuthor("acc-1"),
+			expect:    `worklogAuthor = "acc-1"`,
+			expectErr: ErrUnbalanced,
+		},
+		{
+			name:      "custom reject paren closed before ordering",
+			query:     New().Custom("a = 1) ORDER BY (rank"),
+			expect:    "",
+			expectErr: ErrUnbalanced,
+		},
+		{
+			name:   "blank custom",
+			query:  New().Custom("  "),
+			expect: "",
+		},
+		{
+			name:   "order by quoted field",
+			query:  New().OrderBy("Story Points", false).OrderBy("key", true),
+			expect: `ORDER BY "Story Points" ASC, key DESC`,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expect, tc.query.String())
+			if tc.expectErr != nil {
+				require.ErrorIs(t, tc.query.Err(), tc.expectErr)
+				return
+			}
+			require.NoError(t, tc.query.Err())
+		})
+	}
+}
+
+func TestSearchRequest(t *testing.T) {
+	tcs := []struct {
+		name    string
+		request SearchRequest
+		expect  string
+	}{
+		{
+			name: "v2 page",
+			request: SearchRequest{
+				JQL:        `summary ~ "say \"hi\""`,
+				StartAt:    50,
+				MaxResults: 50,
+				Fields:     []string{"worklog"},
+			},
+			expect: `{"jql":"summary ~ \"say \\\"hi\\\"\"","startAt":50,"maxResults":50,"fields":["worklog"]}`,
+		},
+		{
+			name: "v3 page",
+			request: SearchRequest{
+				JQL:           "project = A",
+				NextPageToken: `tok"en`,
+				MaxResults:    50,
+				Fields:        []string{"worklog"},
+			},
+			expect: `{"jql":"project = A","nextPageToken":"tok\"en","maxResults":50,"fields":["worklog"]}`,
+		},
+		{
+			name:    "validation only",
+			request: SearchRequest{JQL: "project = A", Fields: []string{}},
+			expect:  `{"jql":"project = A","maxResults":0,"fields":[]}`,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := tc.request.Marshal()
+			require.NoError(t, err)
+			require.JSONEq(t, tc.expect, string(res))
+
+			var decoded SearchRequest
+			require.NoError(t, json.Unmarshal(res, &decoded))
+			require.Equal(t, tc.request, decoded)
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package jql
+
+import "encoding/json"
+
+// SearchRequest is body of v2 search and v3 search/jql, v2 page with
+// StartAt while v3 page with NextPageToken
+type SearchRequest struct {
+	JQL           string   `json:"jql"`
+	StartAt       int      `json:"startAt,omitempty"`
+	NextPageToken string   `json:"nextPageToken,omitempty"`
+	MaxResults    int      `json:"maxResults"`
+	Fields        []string `json:"fields"`
+}
+
+// ParseRequest is body of jql/parse
+type ParseRequest struct {
+	Queries []string `json:"queries"`
+}
+
+// Marshal encode search request as json
+func (r SearchRequest) Marshal() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+// Marshal encode parse request as json
+func (r ParseRequest) Marshal() ([]byte, error) {
+	return json.Marshal(r)
+}
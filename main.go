@@ -1,6 +1,10 @@
 package main
 
 import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
 	"sync"
 	"tui/config"
 	"tui/controller"
@@ -11,21 +15,76 @@ import (
 )
 
 func main() {
+	profile := flag.String("profile", "", "config profile to use, default to ATLASSIAN_PROFILE or default_profile")
+	flag.Parse()
+
+	if args := flag.Args(); len(args) > 0 && args[0] == "auth" {
+		runAuthCommand(*profile, args[1:])
+		return
+	}
+
+	if args := flag.Args(); len(args) > 0 && args[0] == "doctor" {
+		runDoctorCommand(*profile)
+		return
+	}
+
 	var wg sync.WaitGroup
 	var mutex sync.Mutex
 
-	// setup config
-	cfg := config.NewConfig()
+	// setup config, setup wizard only run by itself when there is no profile
+	// yet, broken config is reported instead of being overwritten
+	cfg, err := config.LoadConfig(*profile, config.PromptPassphrase)
+	runSetup := len(flag.Args()) > 0 && flag.Args()[0] == "setup"
+	if err != nil && !runSetup && !config.NeedsSetup(*profile) {
+		fmt.Printf("\033[31;1mconfig\033[0m %v\n       fix it or run `setup` to set up the profile again\n", err)
+		os.Exit(1)
+	}
 
 	// setup program
 	thandler := termhandler.NewTermHandler()
 	thandler.Clear()
 	thandler.HideCursor()
 
+	if err != nil || runSetup {
+		setupWizardCtrlr := controller.NewSetupWizardController(
+			&thandler,
+			&mutex,
+			controller.SetupWizardProps{
+				Width:      80,
+				Height:     16,
+				RenderPosX: 2,
+				RenderPosY: 8,
+				Title:      utils.StrToPtr("Setup"),
+			},
+		)
+
+		cfg = setupWizardCtrlr.Run(*profile, err)
+	}
+
 	globalChan := make(chan interface{})
 
 	service := services.NewService(&wg, &mutex, &thandler, &cfg)
-	service.InitService()
+
+	// startup error widget, block until fetch succeed or user quit
+	errorPanelCtrlr := controller.NewErrorPanelController(
+		&thandler,
+		&mutex,
+		controller.ErrorPanelProps{
+			Width:      80,
+			Height:     4,
+			RenderPosX: 2,
+			RenderPosY: 8,
+			Title:      utils.StrToPtr("Error"),
+		},
+	)
+	for {
+		err := service.InitService(context.Background())
+		if err == nil {
+			break
+		}
+
+		errorPanelCtrlr.Prompt(err)
+	}
 
 	// user filter widget
 	userCtrlr := controller.NewUserController(
@@ -35,7 +94,7 @@ func main() {
 		controller.WindowProps{
 			RenderPosX:   2,
 			RenderPosY:   8,
-			WindowHeight: 12,
+			WindowHeight: 8,
 			WindowWidth:  31,
 			Title:        utils.StrToPtr("Users"),
 		},
@@ -46,17 +105,32 @@ func main() {
 	// dates filter widget
 	dateCtrlr := controller.NewDateController(
 		&thandler,
+		&service,
 		&mutex,
 		globalChan,
 		1,
 		controller.DateProps{
 			RenderPosX: 2,
-			RenderPosY: 24,
+			RenderPosY: 20,
 			Width:      31,
 			Height:     1,
 			Title:      utils.StrToPtr("Date"),
 		})
 
+	// project picker widget, reached with [4]
+	projectCtrlr := controller.NewProjectController(
+		&thandler,
+		&service,
+		&mutex,
+		3,
+		controller.ProjectProps{
+			RenderPosX: 2,
+			RenderPosY: 23,
+			Width:      32,
+			Height:     2,
+			Title:      utils.StrToPtr("Projects"),
+		})
+
 	// dahsboard widget
 	dashboardCtrlr := controller.NewDashboardController(
 		&thandler,
@@ -110,12 +184,44 @@ func main() {
 		},
 	)
 
+	// profile and team switcher overlay, hidden until opened
+	switcherCtrlr := controller.NewSwitcherController(
+		&thandler,
+		&mutex,
+		controller.SwitcherProps{
+			Width:         60,
+			Height:        10,
+			RenderPosX:    34,
+			RenderPosY:    8,
+			ActiveProfile: cfg.GetProfile(),
+			Title:         utils.StrToPtr("Profiles"),
+		},
+	)
+
+	// jql filter overlay, hidden until opened
+	filterCtrlr := controller.NewFilterController(
+		&thandler,
+		&service,
+		&mutex,
+		globalChan,
+		controller.FilterProps{
+			Width:      80,
+			Height:     10,
+			RenderPosX: 34,
+			RenderPosY: 8,
+			Title:      utils.StrToPtr("Filter"),
+		},
+	)
+
 	userCtrlr.ListenFromController()
 	userCtrlr.CreateWindow()
 
 	dateCtrlr.ListenFromController()
 	dateCtrlr.CreateWindow()
 
+	projectCtrlr.ListenFromController()
+	projectCtrlr.CreateWindow()
+
 	dashboardCtrlr.ListenFromController()
 	dashboardCtrlr.CreateWindow()
 
@@ -128,6 +234,9 @@ func main() {
 	guideCtrlr.ListenFromController()
 	guideCtrlr.CreateWindow()
 
+	switcherCtrlr.ListenFromController()
+	filterCtrlr.ListenFromController()
+
 	ctrlrList := controller.ControllerChild{
 		0: userCtrlr.GetChan(),
 		1: dateCtrlr.GetChan(),
@@ -135,6 +244,9 @@ func main() {
 		3: worklogDescCtrlr.GetChan(),
 		4: dashboardCtrlr.GetChan(),
 		5: guideCtrlr.GetChan(),
+		6: switcherCtrlr.GetChan(),
+		7: projectCtrlr.GetChan(),
+		8: filterCtrlr.GetChan(),
 	}
 	ctrl := controller.NewController(
 		&wg,
@@ -142,8 +254,10 @@ func main() {
 		&service,
 		ctrlrList,
 		globalChan,
-		userCtrlr.GetSelectedName,
-		dateCtrlr.GetDates,
+		userCtrlr.GetSelectedAccountId,
+		dateCtrlr.GetRange,
+		projectCtrlr.GetCursorProject,
+		switcherCtrlr.GetSwitchedChan(),
 	)
 
 	if err := thandler.Render(); err != nil {
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// group member endpoint caps page size at 50
+const groupMembersPageSize = 50
+
+// fetchDataCenterUsers build roster from configured group, or from the
+// explicit username list when no group is set
+func (s *ServiceApp) fetchDataCenterUsers(ctx context.Context) ([]userValues, error) {
+	if group := s.getConfig().GetUserGroup(); group != "" {
+		return s.fetchGroupMembers(ctx, group)
+	}
+
+	users := []userValues{}
+	for _, name := range s.getConfig().GetUserList() {
+		user, err := s.fetchUserByName(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+
+		users = append(users, *user)
+	}
+
+	return users, nil
+}
+
+// fetchGroupMembers fetch active members of jira group, following startAt
+// until the last page
+func (s *ServiceApp) fetchGroupMembers(ctx context.Context, group string) ([]userValues, error) {
+	baseURI := s.getConfig().GetAtlassianURL()
+	users := []userValues{}
+	startAt := 0
+
+	for {
+		urlGroupMember := fmt.Sprintf(
+			"%s/rest/api/2/group/member?groupname=%s&includeInactiveUsers=false&startAt=%d&maxResults=%d",
+			baseURI,
+			url.QueryEscape(group),
+			startAt,
+			groupMembersPageSize,
+		)
+		resp, err := s.doRequest(ctx, http.MethodGet, urlGroupMember, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var bodyRes UserRes
+		err = decodeJSON(resp, &bodyRes)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		users = append(users, bodyRes.Values...)
+
+		startAt += len(bodyRes.Values)
+		if bodyRes.IsLast || len(bodyRes.Values) == 0 {
+			break
+		}
+	}
+
+	return users, nil
+}
+
+func (s *ServiceApp) fetchUserByName(ctx context.Context, name string) (*userValues, error) {
+	urlGetUser := fmt.Sprintf(
+		"%s/rest/api/2/user?username=%s",
+		s.getConfig().GetAtlassianURL(),
+		url.QueryEscape(name),
+	)
+	resp, err := s.doRequest(ctx, http.MethodGet, urlGetUser, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var user userValues
+	if err := decodeJSON(resp, &user); err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
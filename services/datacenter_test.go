@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"tui/config"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDataCenter serve group member and user endpoints, rejecting request
+// without bearer token
+func fakeDataCenter(t *testing.T, memberCount int) *httptest.Server {
+	t.Helper()
+
+	userOf := func(i int) userValues {
+		return userValues{
+			Name:        fmt.Sprintf("user.%d", i),
+			Key:         fmt.Sprintf("JIRAUSER%d", i),
+			DisplayName: fmt.Sprintf("User %d", i),
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rest/api/2/group/member", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "developers", r.URL.Query().Get("groupname"))
+
+		startAt, _ := strconv.Atoi(r.URL.Query().Get("startAt"))
+		maxResults, _ := strconv.Atoi(r.URL.Query().Get("maxResults"))
+
+		res := UserRes{StartAt: startAt, MaxResults: maxResults, Total: memberCount, Values: []userValues{}}
+		for i := startAt; i < startAt+maxResults && i < memberCount; i++ {
+			res.Values = append(res.Values, userOf(i))
+		}
+		res.IsLast = startAt+maxResults >= memberCount
+
+		json.NewEncoder(w).Encode(res)
+	})
+	mux.HandleFunc("/rest/api/2/user", func(w http.ResponseWriter, r *http.Request) {
+		var i int
+		_, err := fmt.Sscanf(r.URL.Query().Get("username"), "user.%d", &i)
+		require.NoError(t, err)
+
+		json.NewEncoder(w).Encode(userOf(i))
+	})
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	}))
+}
+
+func TestFetchDataCenterUsers(t *testing.T) {
+	tcs := []struct {
+		name string
+		test func(t *testing.T)
+	}{
+		{
+			name: "roster from every page of group",
+			test: func(t *testing.T) {
+				srv := fakeDataCenter(t, 120)
+				defer srv.Close()
+
+				svc := newTestService(t, srv.URL, 0)
+				fake := svc.config.(*fakeConfig)
+				fake.deployment = config.DeploymentDataCenter
+				fake.userGroup = "developers"
+
+				require.NoError(t, svc.FetchMembers(context.Background()))
+				require.NoError(t, svc.FetchUsers(context.Background()))
+
+				users := svc.GetUserItems()
+				require.Len(t, users, 120)
+				require.Equal(t, UserItem{AccountId: "user.0", DisplayName: "User 0"}, users[0])
+				require.Equal(t, "User 119", users[119].DisplayName)
+			},
+		},
+		{
+			name: "roster from configured usernames",
+			test: func(t *testing.T) {
+				srv := fakeDataCenter(t, 0)
+				defer srv.Close()
+
+				svc := newTestService(t, srv.URL, 0)
+				fake := svc.config.(*fakeConfig)
+				fake.deployment = config.DeploymentDataCenter
+				fake.userList = []string{"user.3", "user.7"}
+
+				require.NoError(t, svc.FetchUsers(context.Background()))
+				require.Equal(t, []UserItem{
+					{AccountId: "user.3", DisplayName: "User 3"},
+					{AccountId: "user.7", DisplayName: "User 7"},
+				}, svc.GetUserItems())
+			},
+		},
+		{
+			name: "cloud basic auth is rejected by data center",
+			test: func(t *testing.T) {
+				srv := fakeDataCenter(t, 1)
+				defer srv.Close()
+
+				svc := newTestService(t, srv.URL, 0)
+				svc.config.(*fakeConfig).userGroup = "developers"
+
+				_, err := svc.fetchGroupMembers(context.Background(), "developers")
+				require.ErrorIs(t, err, ErrUnauthorized)
+			},
+		},
+		{
+			name: "match worklog author by username",
+			test: func(t *testing.T) {
+				svc := newTestService(t, "", 0)
+				svc.config.(*fakeConfig).worklogZone = config.WorklogZoneUTC
+
+				user := userValues{Name: "user.1"}
+				res := svc.mapWorklogData([]WorklogsWorklog{
+					{Author: worklogAuthor{Name: "user.1"}, Started: "2024-03-10T08:00:00.000+0000", TimeSpentSeconds: 60},
+					{Author: worklogAuthor{Name: "user.2"}, Started: "2024-03-10T09:00:00.000+0000", TimeSpentSeconds: 60},
+				}, user, FetchWorklogPayload{From: testFrom, To: testTo})
+				require.Equal(t, 1, res.totalWorklog)
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.test(t)
+		})
+	}
+}
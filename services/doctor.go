@@ -0,0 +1,244 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+	"tui/config"
+	"tui/jql"
+)
+
+// DoctorCheck is result of single endpoint check run by Diagnose, Status is
+// http status of the response or 0 when jira was not reached
+type DoctorCheck struct {
+	Name    string
+	Status  int
+	Err     error
+	Hint    string
+	Skipped bool
+}
+
+// doctorStep is single check, hints are keyed by error kind and override
+// the generic ones
+type doctorStep struct {
+	name  string
+	run   func(ctx context.Context) error
+	hints map[error]string
+}
+
+// Diagnose implements ServiceType.
+// It exercise every endpoint used by the tui in the same order as startup,
+// later checks reuse what earlier ones fetched (account ids, issue id)
+func (s *ServiceApp) Diagnose(ctx context.Context) []DoctorCheck {
+	accountId := ""
+	issueId := ""
+
+	steps := []doctorStep{
+		{
+			name: "myself",
+			run: func(ctx context.Context) error {
+				if err := s.FetchMyself(ctx); err != nil {
+					return err
+				}
+
+				accountId = s.GetMyself().id()
+				return nil
+			},
+			hints: map[error]string{
+				ErrNotFound: "check ATLASSIAN_URL points to your jira site",
+			},
+		},
+	}
+
+	if s.getConfig().GetDeployment() == config.DeploymentDataCenter {
+		steps = append(steps, doctorStep{
+			name: "group members",
+			run: func(ctx context.Context) error {
+				if err := s.FetchUsers(ctx); err != nil {
+					return err
+				}
+
+				if len(s.GetUserItems()) == 0 {
+					return fmt.Errorf("%w: roster is empty", errNoData)
+				}
+				return nil
+			},
+			hints: map[error]string{
+				ErrForbidden: "listing group members needs Browse users and groups permission",
+				ErrNotFound:  "check ATLASSIAN_USER_GROUP and ATLASSIAN_USERS",
+				errNoData:    "check ATLASSIAN_USER_GROUP and ATLASSIAN_USERS",
+			},
+		})
+	} else {
+		steps = append(steps,
+			doctorStep{
+				name: "team members",
+				run: func(ctx context.Context) error {
+					if err := s.FetchMembers(ctx); err != nil {
+						return err
+					}
+
+					s.dataMutex.RLock()
+					members := s.accoundIds
+					s.dataMutex.RUnlock()
+					if len(members) == 0 {
+						return fmt.Errorf("%w: team has no members", errNoData)
+					}
+
+					accountId = members[0].AccountId
+					return nil
+				},
+				hints: map[error]string{
+					ErrForbidden: "your account must be able to view the team",
+					ErrNotFound:  "check ATLASSIAN_ORGANIZATION_ID and ATLASSIAN_TEAM_ID",
+					errNoData:    "check ATLASSIAN_TEAM_ID",
+				},
+			},
+			doctorStep{
+				name: "user bulk",
+				run: func(ctx context.Context) error {
+					if accountId == "" {
+						return errSkipped
+					}
+
+					urlGetUsers := fmt.Sprintf("%s/rest/api/2/user/bulk?accountId=%s", s.baseURL(), accountId)
+					resp, err := s.doRequest(ctx, http.MethodGet, urlGetUsers, nil)
+					if err != nil {
+						return err
+					}
+					defer resp.Body.Close()
+
+					var bodyRes UserRes
+					return decodeJSON(resp, &bodyRes)
+				},
+				hints: map[error]string{
+					ErrForbidden: "reading users needs Browse users and groups global permission",
+				},
+			},
+		)
+	}
+
+	steps = append(steps,
+		doctorStep{
+			name: "search",
+			run: func(ctx context.Context) error {
+				// search/jql reject unbounded query, so bound it by worklog date
+				// like FetchIssues does even when no project is configured
+				today := time.Now()
+				query := jql.New().
+					Project(s.getConfig().GetProjects()...).
+					WorklogDate(today.AddDate(0, 0, -30).Format(time.DateOnly), today.Format(time.DateOnly)).
+					OrderBy("updated", true).
+					String()
+
+				var issues []IssuesWorklog
+				if s.getConfig().GetSearchAPI() == config.SearchAPIV3 {
+					res, err := s.searchIssuesJQL(ctx, fmt.Sprintf("%s/rest/api/3/search/jql", s.baseURL()), query, "")
+					if err != nil {
+						return err
+					}
+					issues = res.Issues
+				} else {
+					res, err := s.searchIssues(ctx, fmt.Sprintf("%s/rest/api/2/search", s.baseURL()), query, 0)
+					if err != nil {
+						return err
+					}
+					issues = res.Issues
+				}
+
+				if len(issues) > 0 {
+					issueId = issues[0].Id
+				}
+				return nil
+			},
+			hints: map[error]string{
+				ErrBadRequest: "check ATLASSIAN_PROJECT holds existing project keys",
+				ErrForbidden:  "your account needs Browse projects permission on ATLASSIAN_PROJECT",
+				ErrNotFound:   "search api not found, check ATLASSIAN_SEARCH_API for this deployment",
+			},
+		},
+		doctorStep{
+			name: "issue worklog",
+			run: func(ctx context.Context) error {
+				if issueId == "" {
+					return errSkipped
+				}
+
+				_, err := s.FetchWorklogs(ctx, issueId, time.Unix(0, 0), time.Now())
+				return err
+			},
+			hints: map[error]string{
+				ErrForbidden: "your account needs Browse projects permission to read worklogs",
+				ErrNotFound:  "issue is hidden by issue security or worklog visibility",
+			},
+		},
+	)
+
+	checks := []DoctorCheck{}
+	for _, step := range steps {
+		err := step.run(ctx)
+		if errors.Is(err, errSkipped) {
+			checks = append(checks, DoctorCheck{Name: step.name, Skipped: true, Hint: "nothing to check, previous step returned no data"})
+			continue
+		}
+
+		checks = append(checks, DoctorCheck{
+			Name:   step.name,
+			Status: doctorStatus(err),
+			Err:    err,
+			Hint:   doctorHint(err, step.hints),
+		})
+	}
+
+	return checks
+}
+
+var (
+	errSkipped = errors.New("skipped")
+	errNoData  = errors.New("no data")
+)
+
+// doctorStatus return http status behind err, 200 for success or empty
+// result and 0 when the request did not get a response
+func doctorStatus(err error) int {
+	if err == nil || errors.Is(err, errNoData) {
+		return http.StatusOK
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode
+	}
+
+	return 0
+}
+
+func doctorHint(err error, hints map[error]string) string {
+	if err == nil {
+		return ""
+	}
+
+	for kind, hint := range hints {
+		if errors.Is(err, kind) {
+			return hint
+		}
+	}
+
+	var apiErr *APIError
+	switch {
+	case errors.Is(err, ErrUnauthorized):
+		return "check ATLASSIAN_USER_TOKEN (and ATLASSIAN_USER_EMAIL on cloud), or run `tui auth login` for oauth"
+	case errors.Is(err, ErrRateLimited):
+		return "jira is rate limiting requests, try again later"
+	case errors.Is(err, ErrMalformedResponse):
+		return "unexpected response, check ATLASSIAN_URL is jira and not a proxy login page"
+	case errors.As(err, &apiErr):
+		return "unexpected response from jira"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "request timed out, check network or raise ATLASSIAN_REQUEST_TIMEOUT"
+	}
+
+	return "could not reach jira, check ATLASSIAN_URL, network and proxy"
+}
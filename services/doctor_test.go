@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"tui/config"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiagnose(t *testing.T) {
+	tcs := []struct {
+		name         string
+		searchAPI    string
+		membersCode  int
+		issues       []IssuesWorklog
+		expectStatus map[string]int
+		expectFailed []string
+		expectSkip   []string
+	}{
+		{
+			name:        "every endpoint pass",
+			membersCode: http.StatusOK,
+			issues:      []IssuesWorklog{{Id: "10"}},
+			expectStatus: map[string]int{
+				"myself":        200,
+				"team members":  200,
+				"user bulk":     200,
+				"search":        200,
+				"issue worklog": 200,
+			},
+		},
+		{
+			name:        "bounded search on v3 without projects",
+			searchAPI:   config.SearchAPIV3,
+			membersCode: http.StatusOK,
+			issues:      []IssuesWorklog{{Id: "10"}},
+			expectStatus: map[string]int{
+				"myself":        200,
+				"team members":  200,
+				"user bulk":     200,
+				"search":        200,
+				"issue worklog": 200,
+			},
+		},
+		{
+			name:        "wrong team and empty project",
+			membersCode: http.StatusNotFound,
+			issues:      []IssuesWorklog{},
+			expectStatus: map[string]int{
+				"myself":       200,
+				"team members": 404,
+				"user bulk":    200,
+				"search":       200,
+			},
+			expectFailed: []string{"team members"},
+			expectSkip:   []string{"issue worklog"},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			mux.HandleFunc("/rest/api/2/myself", func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`{"accountId":"acc-1","displayName":"Andi"}`))
+			})
+			mux.HandleFunc("/gateway/api/public/teams/v1/org/org/teams/team/members", func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.membersCode)
+				w.Write([]byte(`{"results":[{"accountId":"acc-2"}],"pageInfo":{"hasNextPage":false}}`))
+			})
+			mux.HandleFunc("/rest/api/2/user/bulk", func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`{"values":[{"accountId":"acc-1","displayName":"Andi"}]}`))
+			})
+			mux.HandleFunc("/rest/api/2/search", func(w http.ResponseWriter, r *http.Request) {
+				json.NewEncoder(w).Encode(WorklogRes{Total: len(tc.issues), Issues: tc.issues})
+			})
+			mux.HandleFunc("/rest/api/3/search/jql", func(w http.ResponseWriter, r *http.Request) {
+				var body struct {
+					Jql string `json:"jql"`
+				}
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					t.Errorf("decode search body: %v", err)
+				}
+
+				// cloud refuse jql without any restriction
+				if strings.HasPrefix(body.Jql, "ORDER BY") {
+					w.WriteHeader(http.StatusBadRequest)
+					w.Write([]byte(`{"errorMessages":["Unbounded JQL queries are not allowed here."]}`))
+					return
+				}
+				json.NewEncoder(w).Encode(SearchJQLRes{Issues: tc.issues, IsLast: true})
+			})
+			issueWorklogs := func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`{"startAt":0,"maxResults":10,"total":0,"worklogs":[]}`))
+			}
+			mux.HandleFunc("/rest/api/2/issue/10/worklog", issueWorklogs)
+			mux.HandleFunc("/rest/api/3/issue/10/worklog", issueWorklogs)
+			srv := httptest.NewServer(mux)
+			defer srv.Close()
+
+			svc := newTestService(t, srv.URL, 0)
+			if tc.searchAPI != "" {
+				svc.config.(*fakeConfig).searchAPI = tc.searchAPI
+			}
+
+			status := map[string]int{}
+			failed := []string{}
+			skipped := []string{}
+			for _, check := range svc.Diagnose(context.Background()) {
+				if check.Skipped {
+					skipped = append(skipped, check.Name)
+					continue
+				}
+
+				status[check.Name] = check.Status
+				if check.Err != nil {
+					require.NotEmpty(t, check.Hint)
+					failed = append(failed, check.Name)
+				}
+			}
+
+			require.Equal(t, tc.expectStatus, status)
+			require.ElementsMatch(t, tc.expectFailed, failed)
+			require.ElementsMatch(t, tc.expectSkip, skipped)
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+var (
+	ErrBadRequest        = errors.New("bad request")
+	ErrUnauthorized      = errors.New("authentication failed")
+	ErrForbidden         = errors.New("permission denied")
+	ErrNotFound          = errors.New("not found")
+	ErrRateLimited       = errors.New("rate limited")
+	ErrMalformedResponse = errors.New("malformed response")
+
+	// teams api live on site gateway, which only take api token
+	ErrGatewayOAuth = errors.New("atlassian teams api doesn't accept oauth token")
+)
+
+// errorBody is error payload returned by jira rest api, teams api use
+// message instead
+type errorBody struct {
+	ErrorMessages []string          `json:"errorMessages"`
+	Errors        map[string]string `json:"errors"`
+	Message       string            `json:"message"`
+}
+
+// APIError describe non 2xx response from jira, Kind is one of Err* above
+// (or nil for unclassified status) so it can be checked with errors.Is
+type APIError struct {
+	Kind       error
+	StatusCode int
+	Method     string
+	URL        string
+	Messages   []string
+}
+
+func (e *APIError) Error() string {
+	kind := "unexpected status"
+	if e.Kind != nil {
+		kind = e.Kind.Error()
+	}
+
+	msg := fmt.Sprintf("%s (%d)", kind, e.StatusCode)
+	if len(e.Messages) > 0 {
+		msg = fmt.Sprintf("%s: %s", msg, strings.Join(e.Messages, "; "))
+	}
+
+	return msg
+}
+
+func (e *APIError) Unwrap() error {
+	return e.Kind
+}
+
+// newAPIError build APIError from response, it consume the body but leave
+// closing to the caller
+func newAPIError(resp *http.Response) *APIError {
+	apiErr := &APIError{
+		Kind:       errorKind(resp.StatusCode),
+		StatusCode: resp.StatusCode,
+		Messages:   []string{},
+	}
+
+	if resp.Request != nil {
+		apiErr.Method = resp.Request.Method
+		apiErr.URL = resp.Request.URL.String()
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<16))
+	if err != nil || len(body) == 0 {
+		return apiErr
+	}
+
+	var errBody errorBody
+	if err := json.Unmarshal(body, &errBody); err != nil {
+		return apiErr
+	}
+
+	apiErr.Messages = append(apiErr.Messages, errBody.ErrorMessages...)
+
+	// keep field errors in stable order
+	fields := make([]string, 0, len(errBody.Errors))
+	for field := range errBody.Errors {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		apiErr.Messages = append(apiErr.Messages, fmt.Sprintf("%s: %s", field, errBody.Errors[field]))
+	}
+
+	if errBody.Message != "" {
+		apiErr.Messages = append(apiErr.Messages, errBody.Message)
+	}
+
+	return apiErr
+}
+
+func errorKind(code int) error {
+	switch code {
+	case http.StatusBadRequest:
+		return ErrBadRequest
+	case http.StatusUnauthorized:
+		return ErrUnauthorized
+	case http.StatusForbidden:
+		return ErrForbidden
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusTooManyRequests:
+		return ErrRateLimited
+	}
+
+	return nil
+}
+
+// decodeJSON decode response body into v, wrapping failure as
+// ErrMalformedResponse
+func decodeJSON(resp *http.Response, v interface{}) error {
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("%w: %v", ErrMalformedResponse, err)
+	}
+
+	return nil
+}
+
+// ErrorMessage return short human readable message for error returned by
+// service, meant to be rendered inside widgets
+func ErrorMessage(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	hint := ""
+	switch {
+	case errors.Is(err, ErrInvalidJQL):
+		hint = "fix the filter or clear it"
+	case errors.Is(err, ErrUnauthorized):
+		hint = "check ATLASSIAN_USER_TOKEN (and ATLASSIAN_USER_EMAIL on cloud)"
+	case errors.Is(err, ErrForbidden):
+		hint = "your account lacks permission for this resource"
+	case errors.Is(err, ErrNotFound):
+		hint = "check ATLASSIAN_URL, organization, team, group and project"
+	case errors.Is(err, ErrRateLimited):
+		hint = "jira is rate limiting requests, try again later"
+	case errors.Is(err, ErrMalformedResponse):
+		hint = "unexpected response from jira"
+	case errors.Is(err, ErrGatewayOAuth):
+		hint = "set ATLASSIAN_USERS to account ids of the roster"
+	}
+
+	if hint == "" {
+		return err.Error()
+	}
+
+	return fmt.Sprintf("%s, %s", err.Error(), hint)
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"tui/config"
+	"tui/jql"
+)
+
+var ErrInvalidJQL = errors.New("invalid jql")
+
+type Filter struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+	Jql  string `json:"jql"`
+}
+
+type jqlParseRes struct {
+	Queries []struct {
+		Query  string   `json:"query"`
+		Errors []string `json:"errors"`
+	} `json:"queries"`
+}
+
+// ValidateJQL implements ServiceType.
+// It check clause FetchIssues make of jql with jql/parse, data center has no
+// such endpoint so empty search is run instead
+func (s *ServiceApp) ValidateJQL(ctx context.Context, filter string) error {
+	custom := jql.New().Custom(filter)
+	if err := custom.Err(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidJQL, err)
+	}
+
+	query := custom.String()
+	if query == "" {
+		return nil
+	}
+
+	if s.getConfig().GetDeployment() == config.DeploymentDataCenter {
+		payload, err := jql.SearchRequest{JQL: query, Fields: []string{}}.Marshal()
+		if err != nil {
+			return err
+		}
+
+		resp, err := s.doRequest(ctx, http.MethodPost, fmt.Sprintf("%s/rest/api/2/search", s.baseURL()), payload)
+		var apiErr *APIError
+		if errors.Is(err, ErrBadRequest) && errors.As(err, &apiErr) {
+			return fmt.Errorf("%w: %s", ErrInvalidJQL, strings.Join(apiErr.Messages, "; "))
+		}
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		return nil
+	}
+
+	payload, err := jql.ParseRequest{Queries: []string{query}}.Marshal()
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.doRequest(
+		ctx,
+		http.MethodPost,
+		fmt.Sprintf("%s/rest/api/2/jql/parse?validation=strict", s.baseURL()),
+		payload,
+	)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var resBody jqlParseRes
+	if err := decodeJSON(resp, &resBody); err != nil {
+		return err
+	}
+
+	for _, parsed := range resBody.Queries {
+		if len(parsed.Errors) > 0 {
+			return fmt.Errorf("%w: %s", ErrInvalidJQL, strings.Join(parsed.Errors, "; "))
+		}
+	}
+
+	return nil
+}
+
+// FetchFavouriteFilters implements ServiceType.
+func (s *ServiceApp) FetchFavouriteFilters(ctx context.Context) ([]Filter, error) {
+	resp, err := s.doRequest(ctx, http.MethodGet, fmt.Sprintf("%s/rest/api/2/filter/favourite", s.baseURL()), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	filters := []Filter{}
+	if err := decodeJSON(resp, &filters); err != nil {
+		return nil, err
+	}
+
+	return filters, nil
+}
+
+// SetFilter implements ServiceType.
+// Filter is ANDed with user and date clauses of every fetch, empty clear it
+func (s *ServiceApp) SetFilter(jql string) {
+	s.dataMutex.Lock()
+	defer s.dataMutex.Unlock()
+
+	s.filter = strings.TrimSpace(jql)
+}
+
+// GetFilter implements ServiceType.
+func (s *ServiceApp) GetFilter() string {
+	s.dataMutex.RLock()
+	defer s.dataMutex.RUnlock()
+
+	return s.filter
+}
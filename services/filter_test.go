@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"tui/config"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateJQL(t *testing.T) {
+	sent := ""
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rest/api/2/jql/parse", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "strict", r.URL.Query().Get("validation"))
+
+		var body struct {
+			Queries []string `json:"queries"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		sent = body.Queries[0]
+
+		errs := []string{}
+		if body.Queries[0] == "(component =)" {
+			errs = append(errs, "Expecting either a value or a function but got 'EOF'.")
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"queries": []map[string]interface{}{{"query": body.Queries[0], "errors": errs}},
+		})
+	})
+	mux.HandleFunc("/rest/api/2/search", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Jql string `json:"jql"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		sent = body.Jql
+
+		if body.Jql == "(component =)" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"errorMessages":["Error in the JQL Query"]}`))
+			return
+		}
+		w.Write([]byte(`{"startAt":0,"maxResults":0,"total":3,"issues":[]}`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	tcs := []struct {
+		name       string
+		deployment string
+		jql        string
+		expectSent string
+		expectErr  error
+	}{
+		{
+			name:       "valid on cloud",
+			deployment: config.DeploymentCloud,
+			jql:        `component = "API" AND labels = backend`,
+			expectSent: `(component = "API" AND labels = backend)`,
+		},
+		{
+			name:       "invalid on cloud",
+			deployment: config.DeploymentCloud,
+			jql:        "component = ",
+			expectSent: "(component =)",
+			expectErr:  ErrInvalidJQL,
+		},
+		{
+			name:       "valid on data center",
+			deployment: config.DeploymentDataCenter,
+			jql:        "issuetype = Bug",
+			expectSent: "(issuetype = Bug)",
+		},
+		{
+			name:       "invalid on data center",
+			deployment: config.DeploymentDataCenter,
+			jql:        "component = ",
+			expectSent: "(component =)",
+			expectErr:  ErrInvalidJQL,
+		},
+		{
+			name:       "ordering is validated without it",
+			deployment: config.DeploymentCloud,
+			jql:        `summary ~ "sort order by date" ORDER BY rank`,
+			expectSent: `(summary ~ "sort order by date")`,
+		},
+		{
+			name:       "unbalanced parentheses rejected before sending",
+			deployment: config.DeploymentCloud,
+			jql:        "labels = a) OR (labels = b",
+			expectErr:  ErrInvalidJQL,
+		},
+		{
+			name:       "only ordering send nothing",
+			deployment: config.DeploymentCloud,
+			jql:        "ORDER BY rank",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := newTestService(t, srv.URL, 0)
+			svc.config.(*fakeConfig).deployment = tc.deployment
+
+			sent = ""
+			err := svc.ValidateJQL(context.Background(), tc.jql)
+			require.Equal(t, tc.expectSent, sent)
+			if tc.expectErr != nil {
+				require.ErrorIs(t, err, tc.expectErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestFetchFavouriteFilters(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/rest/api/2/filter/favourite", r.URL.Path)
+		w.Write([]byte(`[{"id":"10","name":"Backend bugs","jql":"component = API ORDER BY created"}]`))
+	}))
+	defer srv.Close()
+
+	svc := newTestService(t, srv.URL, 0)
+	filters, err := svc.FetchFavouriteFilters(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []Filter{{Id: "10", Name: "Backend bugs", Jql: "component = API ORDER BY created"}}, filters)
+}
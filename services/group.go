@@ -0,0 +1,53 @@
+package services
+
+import (
+	"context"
+	"sync"
+)
+
+// workerGroup is minimal errgroup, it run tasks on at most limit goroutines,
+// keep the first error and cancel ctx so the remaining tasks stop early
+type workerGroup struct {
+	wg      sync.WaitGroup
+	sem     chan struct{}
+	errOnce sync.Once
+	err     error
+	cancel  context.CancelFunc
+}
+
+func newWorkerGroup(ctx context.Context, limit int) (*workerGroup, context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	return &workerGroup{
+		sem:    make(chan struct{}, limit),
+		cancel: cancel,
+	}, ctx
+}
+
+// Go block until worker slot is free then run f on it
+func (g *workerGroup) Go(f func() error) {
+	g.sem <- struct{}{}
+	g.wg.Add(1)
+
+	go func() {
+		defer func() {
+			<-g.sem
+			g.wg.Done()
+		}()
+
+		if err := f(); err != nil {
+			g.errOnce.Do(func() {
+				g.err = err
+				g.cancel()
+			})
+		}
+	}()
+}
+
+// Wait block until every task is done and return the first error
+func (g *workerGroup) Wait() error {
+	g.wg.Wait()
+	g.cancel()
+
+	return g.err
+}
@@ -1,24 +1,58 @@
 package services
 
 import (
+	"context"
 	"io"
 	"net/http"
 	"time"
+	"tui/auth"
+	"tui/config"
+	"tui/utils"
 )
 
 type ServiceType interface {
-  handleFailedFetch()
-	FetchMembers()
-	FetchUsers()
-	FetchIssues(FetchWorklogPayload) error
-	FetchWorklogs(string) (*WorklogField, error)
-	GetUsersName() []string
+	FetchMembers(context.Context) error
+	FetchUsers(context.Context) error
+	fetchDataCenterUsers(context.Context) ([]userValues, error)
+	fetchGroupMembers(context.Context, string) ([]userValues, error)
+	fetchUserByName(context.Context, string) (*userValues, error)
+	FetchIssues(context.Context, FetchWorklogPayload) error
+	FetchMyself(context.Context) error
+	FetchProjects(context.Context) ([]Project, error)
+	FetchOrgID(context.Context) (string, error)
+	FetchTeams(context.Context, string) ([]Team, error)
+	Diagnose(context.Context) []DoctorCheck
+	searchAllIssues(context.Context, string) (*WorklogRes, error)
+	searchIssues(context.Context, string, string, int) (*WorklogRes, error)
+	searchIssuesJQL(context.Context, string, string, string) (*SearchJQLRes, error)
+	FetchWorklogs(context.Context, string, time.Time, time.Time) (*WorklogField, error)
+	GetUserItems() []UserItem
   GetUser() userValues
 	GetWorklogs() WorklogData
   GetSummaryLog() SummaryLog
-	InitService()
-	createRequest(string, string, io.Reader) (*http.Request, error)
-	formatWorklogsData(WorklogRes) error
-	mapWorklogData([]WorklogsWorklog, map[int]FormattedWorklogData, *int, *int, *int)
+	GetLastError() error
+	GetMyself() userValues
+	GetDefaultAccountId() string
+	LoadProjects(context.Context) error
+	GetProjects() []string
+	GetSelectedProjects() []string
+	ToggleProject(string)
+	ValidateJQL(context.Context, string) error
+	FetchFavouriteFilters(context.Context) ([]Filter, error)
+	SetFilter(string)
+	GetFilter() string
+	GetPeriods() []utils.Period
+	GetWorkingHours() int
+	InitService(context.Context) error
+	SetConfig(config.JiraConfigType)
+	baseURL() string
+	session() (config.JiraConfigType, *http.Client, auth.TokenSourceType)
+	getConfig() config.JiraConfigType
+	createRequest(context.Context, config.JiraConfigType, auth.TokenSourceType, string, string, io.Reader) (*http.Request, error)
+	doRequest(context.Context, string, string, []byte) (*http.Response, error)
+	setLastError(context.Context, *error)
+	formatWorklogsData(context.Context, WorklogRes, userValues, FetchWorklogPayload) error
+	mapWorklogData([]WorklogsWorklog, userValues, FetchWorklogPayload) issueWorklogs
+	worklogLocation(WorklogsWorklog, userValues, map[string]*time.Location) *time.Location
   sortLogs([]Logs, time.Time, Logs) []Logs
 }
@@ -0,0 +1,153 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	retryBaseDelay = 500 * time.Millisecond
+	retryMaxDelay  = 30 * time.Second
+)
+
+// doRequest is shared executor for every call to jira, it retry network
+// failures, 429 and 5xx responses with exponential backoff and jitter while
+// respecting Retry-After header. Caller must close body of returned response
+func (s *ServiceApp) doRequest(
+	ctx context.Context,
+	method string,
+	url string,
+	body []byte,
+) (*http.Response, error) {
+	// fetch cancelled by profile switch must not reach the new profile
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// one snapshot for every attempt, profile may be switched meanwhile
+	cfg, client, tokens := s.session()
+	maxRetries := cfg.GetMaxRetries()
+
+	var lastErr error
+	refreshed := false
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		var bodyReader io.Reader
+		if body != nil {
+			bodyReader = bytes.NewReader(body)
+		}
+
+		req, err := s.createRequest(ctx, cfg, tokens, method, url, bodyReader)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			// cancelled by caller, no point retrying
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+
+			lastErr = err
+			if attempt < maxRetries {
+				if err := s.sleep(ctx, backoffDelay(attempt)); err != nil {
+					return nil, err
+				}
+			}
+			continue
+		}
+
+		if resp.StatusCode < http.StatusBadRequest {
+			return resp, nil
+		}
+
+		apiErr := newAPIError(resp)
+		resp.Body.Close()
+
+		// oauth token may be revoked before its expiry, refresh once and
+		// replay without spending a retry
+		if resp.StatusCode == http.StatusUnauthorized && tokens != nil && !refreshed {
+			refreshed = true
+			tokens.Invalidate()
+			attempt--
+			continue
+		}
+
+		if !isRetryableStatus(resp.StatusCode) {
+			return nil, apiErr
+		}
+
+		lastErr = apiErr
+		delay, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+		if !ok {
+			delay = backoffDelay(attempt)
+		}
+
+		if attempt < maxRetries {
+			if err := s.sleep(ctx, delay); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("giving up after %d attempts: %w", maxRetries+1, lastErr)
+}
+
+// sleepContext wait for d or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func isRetryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+}
+
+// backoffDelay return exponential delay for given attempt with up to 50%
+// random jitter, capped at retryMaxDelay
+func backoffDelay(attempt int) time.Duration {
+	delay := retryBaseDelay << attempt
+	if delay <= 0 || delay > retryMaxDelay {
+		delay = retryMaxDelay
+	}
+
+	jitter := time.Duration(rand.Int63n(int64(delay)/2 + 1))
+	return delay/2 + jitter
+}
+
+// parseRetryAfter support both delay-seconds and HTTP-date form
+func parseRetryAfter(val string, now time.Time) (time.Duration, bool) {
+	if val == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(val); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(val); err == nil {
+		delay := date.Sub(now)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+
+	return 0, false
+}
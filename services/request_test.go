@@ -0,0 +1,249 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+	"tui/auth"
+	"tui/config"
+	"tui/utils"
+
+	"github.com/stretchr/testify/require"
+
+	termhandler "tui/term-handler"
+)
+
+type fakeConfig struct {
+	url         string
+	maxRetries  int
+	worklogZone string
+	searchAPI   string
+	deployment  string
+	userGroup   string
+	userList    []string
+	projects    []string
+}
+
+func (f *fakeConfig) GetProfile() string               { return "" }
+func (f *fakeConfig) GetWorkingHours() int             { return 8 }
+func (f *fakeConfig) GetPeriods() []utils.Period       { return nil }
+func (f *fakeConfig) GetEmail() string                 { return "dev@example.com" }
+func (f *fakeConfig) GetUserToken() string             { return "token" }
+func (f *fakeConfig) GetAtlassianURL() string          { return f.url }
+func (f *fakeConfig) GetOrgID() string                 { return "org" }
+func (f *fakeConfig) GetTeamID() string                { return "team" }
+func (f *fakeConfig) GetProjects() []string            { return f.projects }
+func (f *fakeConfig) GetRequestTimeout() time.Duration { return time.Second }
+func (f *fakeConfig) GetMaxRetries() int               { return f.maxRetries }
+func (f *fakeConfig) GetWorklogZone() string           { return f.worklogZone }
+func (f *fakeConfig) GetSearchAPI() string             { return f.searchAPI }
+func (f *fakeConfig) GetDeployment() string            { return f.deployment }
+func (f *fakeConfig) GetUserGroup() string             { return f.userGroup }
+func (f *fakeConfig) GetUserList() []string            { return f.userList }
+func (f *fakeConfig) GetAuthMode() string              { return config.AuthModeToken }
+func (f *fakeConfig) GetOAuthConfig() auth.OAuthConfig { return auth.OAuthConfig{} }
+func (f *fakeConfig) GetOAuthTokenPath() string        { return "" }
+func (f *fakeConfig) WithTeam(string) config.JiraConfigType {
+	return f
+}
+
+func newTestService(t *testing.T, url string, maxRetries int) *ServiceApp {
+	t.Helper()
+
+	var handler termhandler.TermhandlerType
+	var cfg config.JiraConfigType = &fakeConfig{url: url, maxRetries: maxRetries, worklogZone: config.WorklogZoneAuthor,
+		searchAPI: config.SearchAPIV2, deployment: config.DeploymentCloud,
+	}
+	svc := NewService(new(sync.WaitGroup), new(sync.Mutex), &handler, &cfg).(*ServiceApp)
+	svc.sleep = func(context.Context, time.Duration) error { return nil }
+
+	return svc
+}
+
+func TestDoRequest(t *testing.T) {
+	tcs := []struct {
+		name string
+		test func(t *testing.T)
+	}{
+		{
+			name: "retry on server error until success",
+			test: func(t *testing.T) {
+				var hits int32
+				srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if atomic.AddInt32(&hits, 1) < 3 {
+						w.WriteHeader(http.StatusServiceUnavailable)
+						return
+					}
+					w.WriteHeader(http.StatusOK)
+				}))
+				defer srv.Close()
+
+				svc := newTestService(t, srv.URL, 3)
+				resp, err := svc.doRequest(context.Background(), http.MethodGet, srv.URL, nil)
+				require.NoError(t, err)
+				resp.Body.Close()
+
+				require.Equal(t, http.StatusOK, resp.StatusCode)
+				require.Equal(t, int32(3), atomic.LoadInt32(&hits))
+			},
+		},
+		{
+			name: "respect retry after on rate limit",
+			test: func(t *testing.T) {
+				var hits int32
+				srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if atomic.AddInt32(&hits, 1) == 1 {
+						w.Header().Set("Retry-After", "7")
+						w.WriteHeader(http.StatusTooManyRequests)
+						return
+					}
+					w.WriteHeader(http.StatusOK)
+				}))
+				defer srv.Close()
+
+				svc := newTestService(t, srv.URL, 3)
+				delays := []time.Duration{}
+				svc.sleep = func(_ context.Context, d time.Duration) error {
+					delays = append(delays, d)
+					return nil
+				}
+
+				resp, err := svc.doRequest(context.Background(), http.MethodPost, srv.URL, []byte(`{}`))
+				require.NoError(t, err)
+				resp.Body.Close()
+
+				require.Equal(t, []time.Duration{7 * time.Second}, delays)
+			},
+		},
+		{
+			name: "give up after max retries",
+			test: func(t *testing.T) {
+				var hits int32
+				srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					atomic.AddInt32(&hits, 1)
+					w.WriteHeader(http.StatusBadGateway)
+				}))
+				defer srv.Close()
+
+				svc := newTestService(t, srv.URL, 2)
+				_, err := svc.doRequest(context.Background(), http.MethodGet, srv.URL, nil)
+				require.Error(t, err)
+				require.Equal(t, int32(3), atomic.LoadInt32(&hits))
+			},
+		},
+		{
+			name: "rate limited after max retries",
+			test: func(t *testing.T) {
+				srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusTooManyRequests)
+				}))
+				defer srv.Close()
+
+				svc := newTestService(t, srv.URL, 1)
+				_, err := svc.doRequest(context.Background(), http.MethodGet, srv.URL, nil)
+				require.ErrorIs(t, err, ErrRateLimited)
+			},
+		},
+		{
+			name: "no retry on client error",
+			test: func(t *testing.T) {
+				var hits int32
+				srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					atomic.AddInt32(&hits, 1)
+					w.WriteHeader(http.StatusUnauthorized)
+					w.Write([]byte(`{"errorMessages":["token expired"],"errors":{"jql":"bad field"}}`))
+				}))
+				defer srv.Close()
+
+				svc := newTestService(t, srv.URL, 3)
+				_, err := svc.doRequest(context.Background(), http.MethodGet, srv.URL, nil)
+				require.ErrorIs(t, err, ErrUnauthorized)
+				require.Equal(t, int32(1), atomic.LoadInt32(&hits))
+
+				var apiErr *APIError
+				require.ErrorAs(t, err, &apiErr)
+				require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode)
+				require.Equal(t, []string{"token expired", "jql: bad field"}, apiErr.Messages)
+			},
+		},
+		{
+			name: "refresh oauth token once on unauthorized",
+			test: func(t *testing.T) {
+				var hits int32
+				srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if r.URL.Path == "/token" {
+						w.Write([]byte(`{"access_token":"fresh","refresh_token":"refresh-1","expires_in":3600}`))
+						return
+					}
+
+					atomic.AddInt32(&hits, 1)
+					if r.Header.Get("Authorization") != "Bearer fresh" {
+						w.WriteHeader(http.StatusUnauthorized)
+						return
+					}
+					w.WriteHeader(http.StatusOK)
+				}))
+				defer srv.Close()
+
+				store := auth.NewFileStore(filepath.Join(t.TempDir(), "token.json"))
+				require.NoError(t, store.Save(&auth.Token{
+					AccessToken:  "revoked",
+					RefreshToken: "refresh-0",
+					Expiry:       time.Now().Add(time.Hour),
+				}))
+
+				svc := newTestService(t, srv.URL, 0)
+				svc.tokens = auth.NewTokenSource(auth.OAuthConfig{TokenURL: srv.URL + "/token"}, srv.Client(), store)
+
+				resp, err := svc.doRequest(context.Background(), http.MethodGet, srv.URL, nil)
+				require.NoError(t, err)
+				resp.Body.Close()
+				require.Equal(t, int32(2), atomic.LoadInt32(&hits))
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.test(t)
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tcs := []struct {
+		name   string
+		header string
+		expect time.Duration
+		ok     bool
+	}{
+		{name: "empty", header: "", expect: 0, ok: false},
+		{name: "seconds", header: "120", expect: 2 * time.Minute, ok: true},
+		{name: "http date", header: "Mon, 01 Jan 2024 00:00:30 GMT", expect: 30 * time.Second, ok: true},
+		{name: "date in the past", header: "Sun, 31 Dec 2023 23:00:00 GMT", expect: 0, ok: true},
+		{name: "garbage", header: "soon", expect: 0, ok: false},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			res, ok := parseRetryAfter(tc.header, now)
+			require.Equal(t, tc.ok, ok)
+			require.Equal(t, tc.expect, res)
+		})
+	}
+}
+
+func TestBackoffDelay(t *testing.T) {
+	for attempt := 0; attempt < 10; attempt++ {
+		delay := backoffDelay(attempt)
+		require.Greater(t, delay, time.Duration(0))
+		require.LessOrEqual(t, delay, retryMaxDelay)
+	}
+}
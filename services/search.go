@@ -0,0 +1,125 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"tui/config"
+	"tui/jql"
+)
+
+const searchPageSize = 50
+
+// searchAllIssues walk every page of the search before returning, otherwise
+// users with more than one page of issues get truncated totals. Cloud v3
+// search/jql or legacy v2 search is picked according to config
+func (s *ServiceApp) searchAllIssues(ctx context.Context, query string) (*WorklogRes, error) {
+	baseURI := s.baseURL()
+	allIssues := WorklogRes{Issues: []IssuesWorklog{}}
+
+	if s.getConfig().GetSearchAPI() == config.SearchAPIV3 {
+		url := fmt.Sprintf("%s/rest/api/3/search/jql", baseURI)
+		token := ""
+		for {
+			resBody, err := s.searchIssuesJQL(ctx, url, query, token)
+			if err != nil {
+				return nil, err
+			}
+
+			allIssues.Issues = append(allIssues.Issues, resBody.Issues...)
+
+			if resBody.IsLast || resBody.NextPageToken == "" {
+				break
+			}
+			token = resBody.NextPageToken
+		}
+
+		// search/jql does not report total, every page is fetched anyway
+		allIssues.Total = len(allIssues.Issues)
+		allIssues.MaxResults = len(allIssues.Issues)
+		return &allIssues, nil
+	}
+
+	url := fmt.Sprintf("%s/rest/api/2/search", baseURI)
+	startAt := 0
+	for {
+		resBody, err := s.searchIssues(ctx, url, query, startAt)
+		if err != nil {
+			return nil, err
+		}
+
+		allIssues.Issues = append(allIssues.Issues, resBody.Issues...)
+		allIssues.Total = resBody.Total
+
+		startAt += len(resBody.Issues)
+		if len(resBody.Issues) == 0 || startAt >= resBody.Total {
+			break
+		}
+	}
+	allIssues.MaxResults = len(allIssues.Issues)
+
+	return &allIssues, nil
+}
+
+// searchIssues fetch single page of v2 issue search starting from startAt
+func (s *ServiceApp) searchIssues(
+	ctx context.Context,
+	url string,
+	query string,
+	startAt int,
+) (*WorklogRes, error) {
+	payload, err := jql.SearchRequest{
+		JQL:        query,
+		StartAt:    startAt,
+		MaxResults: searchPageSize,
+		Fields:     []string{"worklog"},
+	}.Marshal()
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := s.doRequest(ctx, http.MethodPost, url, payload)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	var resBody WorklogRes
+	if err := decodeJSON(res, &resBody); err != nil {
+		return nil, err
+	}
+
+	return &resBody, nil
+}
+
+// searchIssuesJQL fetch single page of v3 search/jql, empty token means
+// first page
+func (s *ServiceApp) searchIssuesJQL(
+	ctx context.Context,
+	url string,
+	query string,
+	nextPageToken string,
+) (*SearchJQLRes, error) {
+	payload, err := jql.SearchRequest{
+		JQL:           query,
+		NextPageToken: nextPageToken,
+		MaxResults:    searchPageSize,
+		Fields:        []string{"worklog"},
+	}.Marshal()
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := s.doRequest(ctx, http.MethodPost, url, payload)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	var resBody SearchJQLRes
+	if err := decodeJSON(res, &resBody); err != nil {
+		return nil, err
+	}
+
+	return &resBody, nil
+}
@@ -1,53 +1,79 @@
 package services
 
 import (
-	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
-	"os"
-	"strings"
 	"sync"
 	"time"
+	"tui/auth"
 	"tui/config"
+	"tui/jql"
 	"tui/utils"
 
 	termhandler "tui/term-handler"
 )
 
+const (
+	membersPageSize = 50
+	usersBatchSize  = 50
+	// worklog endpoint caps page size at 5000
+	worklogsPageSize = 5000
+	worklogWorkers   = 8
+)
+
+// FetchWorklogPayload select whose worklogs to fetch, AccountId hold
+// username on data center. Empty Projects mean every visible project
 type FetchWorklogPayload struct {
-	Name  string
-	Year  int
-	Month int
+	AccountId string
+	Projects  []string
+	Filter    string
+	From      time.Time // first day, inclusive
+	To        time.Time // last day, inclusive
+	Label     string    // name of period, empty for free range
 }
 
+// WorklogData is worklogs grouped by day of range, day 1 is From. LastDate
+// is the last day holding worklog
 type WorklogData struct {
-	LastDate int
-	Name     string
-	Month    int
-	Year     int
-	Data     map[int]FormattedWorklogData
+	LastDate  int
+	AccountId string
+	Name      string
+	Filter    string
+	From      time.Time
+	To        time.Time
+	Label     string
+	Data      map[int]FormattedWorklogData
 }
 
 type SummaryLog struct {
 	TotalBacklog   int
 	TotalWorklog   int
 	TotalTimeSpent int
+	SkippedWorklog int // unreadable worklogs left out of the totals
 }
 
 type ServiceApp struct {
 	wg         *sync.WaitGroup
-	localWg    *sync.WaitGroup
 	mutex      *sync.Mutex
+	dataMutex  *sync.RWMutex
 	handler    termhandler.TermhandlerType
 	config     config.JiraConfigType
 	client     *http.Client
+	tokens     auth.TokenSourceType
+	sleep      func(context.Context, time.Duration) error
 	accoundIds resultMember
 	users      []userValues
+	projects   []string
+	selection  map[string]bool // selected projects
+	filter     string
+	myself     userValues
 	worklogs   WorklogData
 	summaryLog SummaryLog
+	lastError  error
 }
 
 func NewService(
@@ -56,62 +82,137 @@ func NewService(
 	handler *termhandler.TermhandlerType,
 	config *config.JiraConfigType,
 ) ServiceType {
+	client := &http.Client{
+		Timeout: (*config).GetRequestTimeout(),
+	}
+
 	return &ServiceApp{
-		wg:      wg,
-		mutex:   mutex,
-		localWg: new(sync.WaitGroup),
-		handler: *handler,
-		config:  *config,
-		client: &http.Client{
-			Timeout: 5 * time.Second,
-		},
+		wg:         wg,
+		mutex:      mutex,
+		dataMutex:  new(sync.RWMutex),
+		handler:    *handler,
+		config:     *config,
+		client:     client,
+		tokens:     newTokenSource(*config, client),
+		sleep:      sleepContext,
 		accoundIds: resultMember{},
 		users:      []userValues{},
+		projects:   []string{},
+		selection:  map[string]bool{},
 		worklogs:   WorklogData{},
 		summaryLog: SummaryLog{},
 	}
 }
 
 func (s *ServiceApp) createRequest(
+	ctx context.Context,
+	cfg config.JiraConfigType,
+	tokens auth.TokenSourceType,
 	method string,
 	url string,
 	body io.Reader,
 ) (*http.Request, error) {
-	req, err := http.NewRequest(method, url, body)
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
 	if err != nil {
 		return nil, err
 	}
 
-	emailENV := s.config.GetEmail()
-	userTokenENV := s.config.GetUserToken()
-
-	basicA64 := fmt.Sprintf("%s:%s", emailENV, userTokenENV)
-	basicAuth := fmt.Sprintf("Basic %s", base64.StdEncoding.EncodeToString([]byte(basicA64)))
+	// oauth and data center personal access token are sent as bearer, cloud
+	// api token need basic auth with account email
+	var authorization string
+	switch {
+	case tokens != nil:
+		token, err := tokens.Token(ctx)
+		if err != nil {
+			return nil, err
+		}
+		authorization = fmt.Sprintf("Bearer %s", token.AccessToken)
+	case cfg.GetDeployment() == config.DeploymentDataCenter:
+		authorization = fmt.Sprintf("Bearer %s", cfg.GetUserToken())
+	default:
+		emailENV := cfg.GetEmail()
+		userTokenENV := cfg.GetUserToken()
+
+		basicA64 := fmt.Sprintf("%s:%s", emailENV, userTokenENV)
+		authorization = fmt.Sprintf("Basic %s", base64.StdEncoding.EncodeToString([]byte(basicA64)))
+	}
 
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json")
-	req.Header.Set("Authorization", basicAuth)
+	req.Header.Set("Authorization", authorization)
 	return req, nil
 }
 
-func (s *ServiceApp) handleFailedFetch() {
-	s.handler.MoveCursor(termhandler.Position{2, 2})
-	s.handler.Draw(strings.Repeat(" ", 100))
-	s.handler.Render()
+// newTokenSource return source of oauth token refreshed on demand by
+// createRequest, nil when api token is used
+func newTokenSource(cfg config.JiraConfigType, client *http.Client) auth.TokenSourceType {
+	if cfg.GetAuthMode() != config.AuthModeOAuth {
+		return nil
+	}
 
-	s.handler.MoveCursor(termhandler.Position{1, 1})
-	s.handler.Draw("failed to fetch member team, please check your internet connection")
-	s.handler.ShowCursor()
-	s.handler.Render()
+	return auth.NewTokenSource(
+		cfg.GetOAuthConfig(),
+		client,
+		auth.NewFileStore(cfg.GetOAuthTokenPath()),
+	)
+}
+
+// baseURL return root of jira rest api, oauth token only work through
+// api.atlassian.com gateway of the site chosen at login
+func (s *ServiceApp) baseURL() string {
+	cfg, _, tokens := s.session()
+	if tokens != nil {
+		return fmt.Sprintf("%s/%s", auth.AtlassianAPIURL, tokens.CloudID())
+	}
+
+	return cfg.GetAtlassianURL()
+}
+
+// session return config, client and token source of current profile in one
+// read, SetConfig may swap them while cancelled fetches are still running
+func (s *ServiceApp) session() (config.JiraConfigType, *http.Client, auth.TokenSourceType) {
+	s.dataMutex.RLock()
+	defer s.dataMutex.RUnlock()
 
-	os.Exit(0)
+	return s.config, s.client, s.tokens
+}
+
+// getConfig return config of current profile, see session
+func (s *ServiceApp) getConfig() config.JiraConfigType {
+	cfg, _, _ := s.session()
+	return cfg
 }
 
 // FetchMembers implements ServiceType.
-func (s *ServiceApp) FetchMembers() {
-	baseURI := s.config.GetAtlassianURL()
-	teamId := s.config.GetTeamID()
-	orgId := s.config.GetOrgID()
+func (s *ServiceApp) FetchMembers(ctx context.Context) (err error) {
+	defer s.setLastError(ctx, &err)
+
+	// data center has no atlassian teams, roster is resolved by FetchUsers
+	if s.getConfig().GetDeployment() == config.DeploymentDataCenter {
+		return nil
+	}
+
+	// explicit account ids replace team roster
+	if ids := s.getConfig().GetUserList(); len(ids) > 0 {
+		members := resultMember{}
+		for _, id := range ids {
+			members = append(members, member{AccountId: id})
+		}
+
+		s.dataMutex.Lock()
+		s.accoundIds = members
+		s.dataMutex.Unlock()
+		return nil
+	}
+
+	if _, _, tokens := s.session(); tokens != nil {
+		return ErrGatewayOAuth
+	}
+
+	cfg := s.getConfig()
+	baseURI := cfg.GetAtlassianURL()
+	teamId := cfg.GetTeamID()
+	orgId := cfg.GetOrgID()
 
 	urlFetchMember := fmt.Sprintf(
 		"%s/gateway/api/public/teams/v1/org/%s/teams/%s/members",
@@ -120,126 +221,226 @@ func (s *ServiceApp) FetchMembers() {
 		teamId,
 	)
 
-	req, err := s.createRequest(http.MethodPost, urlFetchMember, nil)
-	if err != nil {
-		panic(fmt.Sprintf("error creating request member: %v ", err))
-	}
+	// follow pageInfo cursor until whole team roster is fetched
+	members := resultMember{}
+	cursor := ""
+	for {
+		payload, err := json.Marshal(teamMemberReq{First: membersPageSize, After: cursor})
+		if err != nil {
+			return err
+		}
 
-	resp, err := s.client.Do(req)
-	if err != nil {
-		s.handleFailedFetch()
-	}
-	defer resp.Body.Close()
+		resp, err := s.doRequest(ctx, http.MethodPost, urlFetchMember, payload)
+		if err != nil {
+			return err
+		}
 
-	decoder := json.NewDecoder(resp.Body)
-	var resBody TeamMemberRes
-	decoder.Decode(&resBody)
+		var resBody TeamMemberRes
+		err = decodeJSON(resp, &resBody)
+		resp.Body.Close()
+		if err != nil {
+			return err
+		}
 
-	s.accoundIds = resBody.Results
-}
+		members = append(members, resBody.Results...)
 
-func (s *ServiceApp) FetchUsers() {
-	baseURI := s.config.GetAtlassianURL()
-	params := ""
-	for _, ids := range s.accoundIds {
-		params += fmt.Sprintf("accountId=%s&", ids.AccountId)
+		if !resBody.PageInfo.HasNextPage || resBody.PageInfo.EndCursor == "" {
+			break
+		}
+		cursor = resBody.PageInfo.EndCursor
 	}
 
-	urlGetUsers := fmt.Sprintf(
-		"%s/rest/api/2/user/bulk?maxResults=50&%s",
-		baseURI,
-		params,
-	)
-	req, err := s.createRequest(http.MethodGet, urlGetUsers, nil)
-	if err != nil {
-		panic(fmt.Sprintf("error creating request user: %v ", err))
-	}
+	s.dataMutex.Lock()
+	s.accoundIds = members
+	s.dataMutex.Unlock()
+	return nil
+}
 
-	resp, err := s.client.Do(req)
-	if err != nil {
-		s.handleFailedFetch()
+func (s *ServiceApp) FetchUsers(ctx context.Context) (err error) {
+	defer s.setLastError(ctx, &err)
+
+	if s.getConfig().GetDeployment() == config.DeploymentDataCenter {
+		users, err := s.fetchDataCenterUsers(ctx)
+		if err != nil {
+			return err
+		}
+
+		s.dataMutex.Lock()
+		s.users = users
+		s.dataMutex.Unlock()
+		return nil
 	}
-	defer resp.Body.Close()
 
-	decoder := json.NewDecoder(resp.Body)
-	var bodyRes UserRes
-	decoder.Decode(&bodyRes)
+	baseURI := s.baseURL()
+	users := []userValues{}
 
-	s.users = bodyRes.Values
-}
+	s.dataMutex.RLock()
+	accoundIds := s.accoundIds
+	s.dataMutex.RUnlock()
 
-func (s *ServiceApp) FetchIssues(param FetchWorklogPayload) error {
-	baseURI := s.config.GetAtlassianURL()
-	project := s.config.GetJiraProject()
+	// user bulk only return maxResults items per call and long query string
+	// got rejected, so split account ids into batches
+	for i := 0; i < len(accoundIds); i += usersBatchSize {
+		end := i + usersBatchSize
+		if end > len(accoundIds) {
+			end = len(accoundIds)
+		}
 
-	var user userValues
-	url := fmt.Sprintf("%s/rest/api/2/search", baseURI)
+		params := ""
+		for _, ids := range accoundIds[i:end] {
+			params += fmt.Sprintf("accountId=%s&", ids.AccountId)
+		}
 
-	fromDate, toDate := utils.CalculateRangeDateInMonth(param.Month, param.Year)
-	getSpesificUser(s.users, &user, param.Name)
+		urlGetUsers := fmt.Sprintf(
+			"%s/rest/api/2/user/bulk?maxResults=%d&%s",
+			baseURI,
+			usersBatchSize,
+			params,
+		)
+		resp, err := s.doRequest(ctx, http.MethodGet, urlGetUsers, nil)
+		if err != nil {
+			return err
+		}
 
-	payload := fmt.Sprintf(`{
-    "jql": "project IN (%s) AND assignee = %s AND worklogDate >= %s AND worklogDate <= %s ORDER BY created DESC",
-    "fields": ["worklog"]
-    }`, project, user.AccountId, fromDate, toDate)
+		var bodyRes UserRes
+		err = decodeJSON(resp, &bodyRes)
+		resp.Body.Close()
+		if err != nil {
+			return err
+		}
 
-	payloadReader := bytes.NewReader([]byte(payload))
-	req, err := s.createRequest(http.MethodPost, url, payloadReader)
-	if err != nil {
-		return err
+		users = append(users, bodyRes.Values...)
 	}
 
-	res, err := s.client.Do(req)
+	s.dataMutex.Lock()
+	s.users = users
+	s.dataMutex.Unlock()
+	return nil
+}
+
+func (s *ServiceApp) FetchIssues(ctx context.Context, param FetchWorklogPayload) (err error) {
+	defer s.setLastError(ctx, &err)
+
+	var user userValues
+
+	s.dataMutex.RLock()
+	getSpesificUser(s.users, &user, param.AccountId)
+	s.dataMutex.RUnlock()
+
+	query := jql.New().
+		Custom(param.Filter).
+		Project(param.Projects...).
+		WorklogAuthor(user.id()).
+		WorklogDate(param.From.Format(time.DateOnly), param.To.Format(time.DateOnly)).
+		OrderBy("created", true)
+	if err := query.Err(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidJQL, err)
+	}
+
+	allIssues, err := s.searchAllIssues(ctx, query.String())
 	if err != nil {
 		return err
 	}
-	defer res.Body.Close()
-
-	decoder := json.NewDecoder(res.Body)
-	var resBody WorklogRes
-	decoder.Decode(&resBody)
-	s.worklogs.Month = param.Month
-	s.worklogs.Year = param.Year
-	s.worklogs.Name = param.Name
 
-	if err = s.formatWorklogsData(resBody); err != nil {
+	if err := s.formatWorklogsData(ctx, *allIssues, user, param); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func (s *ServiceApp) FetchWorklogs(id string) (*WorklogField, error) {
-	baseURI := s.config.GetAtlassianURL()
-	url := fmt.Sprintf("%s/rest/api/2/issue/%s/worklog", baseURI, id)
-	req, err := s.createRequest(http.MethodGet, url, nil)
-	if err != nil {
-		return nil, err
+// FetchWorklogs fetch every worklog of issue id started between startedAfter
+// and startedBefore, following startAt until the last page
+func (s *ServiceApp) FetchWorklogs(
+	ctx context.Context,
+	id string,
+	startedAfter time.Time,
+	startedBefore time.Time,
+) (*WorklogField, error) {
+	baseURI := s.baseURL()
+	result := WorklogField{Worklogs: []WorklogsWorklog{}}
+	startAt := 0
+
+	// follow search api so comments come in the same shape
+	apiVersion := "2"
+	if s.getConfig().GetSearchAPI() == config.SearchAPIV3 {
+		apiVersion = "3"
 	}
 
-	res, err := s.client.Do(req)
-	if err != nil {
-		return nil, err
+	for {
+		url := fmt.Sprintf(
+			"%s/rest/api/%s/issue/%s/worklog?startAt=%d&maxResults=%d&startedAfter=%d&startedBefore=%d",
+			baseURI,
+			apiVersion,
+			id,
+			startAt,
+			worklogsPageSize,
+			startedAfter.UnixMilli(),
+			startedBefore.UnixMilli(),
+		)
+		res, err := s.doRequest(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var resBody WorklogField
+		err = decodeJSON(res, &resBody)
+		res.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		result.Worklogs = append(result.Worklogs, resBody.Worklogs...)
+		result.Total = resBody.Total
+
+		startAt += len(resBody.Worklogs)
+		if len(resBody.Worklogs) == 0 || startAt >= resBody.Total {
+			break
+		}
 	}
-	defer res.Body.Close()
-	var resBody WorklogField
-	json.NewDecoder(res.Body).Decode(&resBody)
-	return &resBody, nil
+	result.MaxResults = len(result.Worklogs)
+
+	return &result, nil
 }
 
-// GetUsersData implements ServiceType.
-func (s *ServiceApp) GetUsersName() []string {
-	res := []string{}
+// GetUserItems implements ServiceType.
+func (s *ServiceApp) GetUserItems() []UserItem {
+	s.dataMutex.RLock()
+	defer s.dataMutex.RUnlock()
+
+	res := []UserItem{}
 	for _, user := range s.users {
-		res = append(res, user.DisplayName)
+		res = append(res, UserItem{AccountId: user.id(), DisplayName: user.DisplayName})
 	}
 	return res
 }
 
+// GetDefaultAccountId return authenticated user when it is on the roster,
+// otherwise the first user
+func (s *ServiceApp) GetDefaultAccountId() string {
+	s.dataMutex.RLock()
+	defer s.dataMutex.RUnlock()
+
+	if len(s.users) == 0 {
+		return ""
+	}
+
+	for _, user := range s.users {
+		if s.myself.id() != "" && user.id() == s.myself.id() {
+			return user.id()
+		}
+	}
+
+	return s.users[0].id()
+}
+
 func (s *ServiceApp) GetUser() userValues {
+	s.dataMutex.RLock()
+	defer s.dataMutex.RUnlock()
+
 	var user userValues
 	for _, val := range s.users {
-		if val.DisplayName == s.worklogs.Name {
+		if val.id() == s.worklogs.AccountId {
 			user = val
 			break
 		}
@@ -249,109 +450,193 @@ func (s *ServiceApp) GetUser() userValues {
 }
 
 func (s *ServiceApp) GetWorklogs() WorklogData {
+	s.dataMutex.RLock()
+	defer s.dataMutex.RUnlock()
+
 	return s.worklogs
 }
 
 func (s *ServiceApp) GetSummaryLog() SummaryLog {
+	s.dataMutex.RLock()
+	defer s.dataMutex.RUnlock()
+
 	return s.summaryLog
 }
 
-func getSpesificUser(items []userValues, item *userValues, name string) {
+// GetLastError return error of latest fetch, nil when it succeed
+func (s *ServiceApp) GetLastError() error {
+	s.dataMutex.RLock()
+	defer s.dataMutex.RUnlock()
+
+	return s.lastError
+}
+
+// GetPeriods return periods of current profile, selected one first
+func (s *ServiceApp) GetPeriods() []utils.Period {
+	return s.getConfig().GetPeriods()
+}
+
+// GetWorkingHours return length of working day of current profile
+func (s *ServiceApp) GetWorkingHours() int {
+	return s.getConfig().GetWorkingHours()
+}
+
+// setLastError record result of fetch, skipped when ctx is cancelled so
+// abandoned fetch won't override error of the one replacing it
+func (s *ServiceApp) setLastError(ctx context.Context, err *error) {
+	if ctx.Err() != nil {
+		return
+	}
+
+	s.dataMutex.Lock()
+	s.lastError = *err
+	s.dataMutex.Unlock()
+}
+
+func getSpesificUser(items []userValues, item *userValues, accountId string) {
 	for _, user := range items {
-		if user.DisplayName == name {
+		if user.id() == accountId {
 			*item = user
 			break
 		}
 	}
 }
 
-func (s *ServiceApp) formatWorklogsData(worklogData WorklogRes) error {
+func (s *ServiceApp) formatWorklogsData(
+	ctx context.Context,
+	worklogData WorklogRes,
+	user userValues,
+	param FetchWorklogPayload,
+) error {
+	// pad range by one day on each side, worklogs outside selected range
+	// still filtered by mapWorklogData according to its own offset
+	from, to := rangeDates(param)
+	startedAfter := from.AddDate(0, 0, -1)
+	startedBefore := to.AddDate(0, 0, 2)
+
+	// every worker only write its own slot, results merged after all done
+	results := make([]issueWorklogs, len(worklogData.Issues))
+	group, groupCtx := newWorkerGroup(ctx, worklogWorkers)
+
+	for i, issue := range worklogData.Issues {
+		group.Go(func() error {
+			worklogs := issue.Fields.Worklog.Worklogs
+
+			// search only embed first page of worklogs (20 items)
+			if issue.Fields.Worklog.Total > len(worklogs) {
+				wlField, err := s.FetchWorklogs(groupCtx, issue.Id, startedAfter, startedBefore)
+				if err != nil {
+					return err
+				}
+				worklogs = wlField.Worklogs
+			}
+
+			results[i] = s.mapWorklogData(worklogs, user, param)
+			return nil
+		})
+	}
+
+	groupErr := group.Wait()
+
 	wkData := map[int]FormattedWorklogData{}
-	var isError error
 	lastDate := 0
 	totalTimeSpent := 0
 	totalWorklog := 0
+	skipped := 0
 
-	for _, issue := range worklogData.Issues {
-		s.localWg.Add(1)
-		go func(issueItem IssuesWorklog) {
-			if issueItem.Fields.Worklog.Total > 20 {
-				wlField, err := s.FetchWorklogs(issueItem.Id)
-				if err != nil {
-					isError = err
-				}
-
-				s.mapWorklogData(
-					wlField.Worklogs,
-					wkData,
-					&lastDate,
-					&totalTimeSpent,
-					&totalWorklog,
-				)
-			} else {
-				s.mapWorklogData(
-					issueItem.Fields.Worklog.Worklogs,
-					wkData,
-					&lastDate,
-					&totalTimeSpent,
-					&totalWorklog,
-				)
+	for _, res := range results {
+		if res.lastDate > lastDate {
+			lastDate = res.lastDate
+		}
+		totalWorklog += res.totalWorklog
+		totalTimeSpent += res.totalTimeSpent
+		skipped += res.skipped
+
+		for day, dayData := range res.data {
+			merged := wkData[day]
+			merged.TimeSpent += dayData.TimeSpent
+			for _, log := range dayData.Logs {
+				merged.Logs = s.sortLogs(merged.Logs, log.Started, log)
 			}
-		}(issue)
+			wkData[day] = merged
+		}
+	}
+
+	// check ctx while holding dataMutex so cancelled fetch never override
+	// result of the fetch replacing it
+	s.dataMutex.Lock()
+	defer s.dataMutex.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return err
 	}
 
-	s.localWg.Wait()
+	if groupErr != nil {
+		return groupErr
+	}
 
 	s.summaryLog = SummaryLog{
 		TotalBacklog:   worklogData.Total,
 		TotalWorklog:   totalWorklog,
 		TotalTimeSpent: totalTimeSpent,
+		SkippedWorklog: skipped,
 	}
 
 	s.worklogs = WorklogData{
-		Month:    s.worklogs.Month,
-		Year:     s.worklogs.Year,
-		Name:     s.worklogs.Name,
-		LastDate: lastDate,
-		Data:     wkData,
-	}
-
-	if isError != nil {
-		return isError
+		From:      param.From,
+		To:        param.To,
+		Label:     param.Label,
+		AccountId: user.id(),
+		Name:      user.DisplayName,
+		Filter:    param.Filter,
+		LastDate:  lastDate,
+		Data:      wkData,
 	}
 
 	return nil
 }
 
+// mapWorklogData aggregate worklogs of single issue by day of range in the
+// configured zone, it touch no shared state so it is safe to call from many
+// workers. Worklogs with unparsable started are counted as skipped
 func (s *ServiceApp) mapWorklogData(
 	arr []WorklogsWorklog,
-	wkData map[int]FormattedWorklogData,
-	lastDate *int,
-	totalTimeSpent *int,
-	totalWorklog *int,
-) {
+	user userValues,
+	param FetchWorklogPayload,
+) issueWorklogs {
 	iso8601Layout := "2006-01-02T15:04:05-0700"
 	hhMmLayout := "15:04"
+	res := issueWorklogs{data: map[int]FormattedWorklogData{}}
+	locations := map[string]*time.Location{}
+	from, to := rangeDates(param)
 
 	for _, worklog := range arr {
-		parsed, _ := time.Parse(iso8601Layout, worklog.Started)
-		if int(parsed.Month()) != s.worklogs.Month {
+		// issue may have worklogs from other people, only count selected user
+		if worklog.Author.id() != user.id() {
 			continue
 		}
 
-		s.mutex.Lock()
-		day := parsed.Day()
-		if day > *lastDate {
-			*lastDate = day
+		// one bad worklog shouldn't take down the whole grid
+		started, err := time.Parse(iso8601Layout, worklog.Started)
+		if err != nil {
+			res.skipped++
+			continue
 		}
-		s.mutex.Unlock()
 
-		timeSpent := worklog.TimeSpentSeconds
+		parsed := started.In(s.worklogLocation(worklog, user, locations))
+		date := utils.TruncateDate(parsed)
+		if date.Before(from) || date.After(to) {
+			continue
+		}
 
-		s.mutex.Lock()
-		*totalWorklog += 1
-		*totalTimeSpent += timeSpent
-		s.mutex.Unlock()
+		day := int(date.Sub(from).Hours()/24) + 1
+		if day > res.lastDate {
+			res.lastDate = day
+		}
+
+		timeSpent := worklog.TimeSpentSeconds
+		res.totalWorklog += 1
+		res.totalTimeSpent += timeSpent
 
 		startTime := fmt.Sprintf("%s", parsed.Format(hhMmLayout))
 		endTime := fmt.Sprintf(
@@ -360,31 +645,57 @@ func (s *ServiceApp) mapWorklogData(
 		)
 		timeRange := fmt.Sprintf("%s - %s", startTime, endTime)
 
-		s.mutex.Lock()
-		wkLogs := wkData[day].Logs
-		logs := []Logs{}
-
-		if len(wkLogs) > 0 {
-			logs = s.sortLogs(wkLogs, parsed, Logs{
-				Comment:   worklog.Comment,
+		res.data[day] = FormattedWorklogData{
+			TimeSpent: res.data[day].TimeSpent + timeSpent,
+			Logs: s.sortLogs(res.data[day].Logs, parsed, Logs{
+				Comment:   string(worklog.Comment),
 				TimeRange: timeRange,
 				Started:   parsed,
-			})
-		} else {
-			logs = append(wkLogs, Logs{
-				Comment:   worklog.Comment,
-				TimeRange: timeRange,
-				Started:   parsed,
-			})
+			}),
+		}
+	}
+
+	return res
+}
+
+// rangeDates return range of param as UTC dates, calendar day is all that
+// matter when bucketing worklogs
+func rangeDates(param FetchWorklogPayload) (time.Time, time.Time) {
+	return utils.TruncateDate(param.From), utils.TruncateDate(param.To)
+}
+
+// worklogLocation resolve zone to bucket worklog into, viewer zone is zone
+// of the viewed user while author zone fallback to it. UTC is used when jira
+// return unknown zone. Loaded zones cached in locations
+func (s *ServiceApp) worklogLocation(
+	worklog WorklogsWorklog,
+	user userValues,
+	locations map[string]*time.Location,
+) *time.Location {
+	zones := []string{worklog.Author.TimeZone, user.TimeZone}
+	switch s.getConfig().GetWorklogZone() {
+	case config.WorklogZoneViewer:
+		zones = []string{user.TimeZone}
+	case config.WorklogZoneUTC:
+		return time.UTC
+	}
+
+	for _, tz := range zones {
+		if tz == "" {
+			continue
 		}
 
-		wkData[day] = FormattedWorklogData{
-			TimeSpent: wkData[day].TimeSpent + timeSpent,
-			Logs:      logs,
+		if loc, ok := locations[tz]; ok {
+			return loc
+		}
+
+		if loc, err := time.LoadLocation(tz); err == nil {
+			locations[tz] = loc
+			return loc
 		}
-		s.mutex.Unlock()
 	}
-	s.localWg.Done()
+
+	return time.UTC
 }
 
 // Does place new item in exact location sequentially according to date clock
@@ -411,12 +722,54 @@ func (s *ServiceApp) sortLogs(arrLogs []Logs, current time.Time, newItem Logs) [
 	return res
 }
 
+// SetConfig implements ServiceType.
+// It point service at another profile or team and drop data of the previous
+// one. Requests in flight keep the session they started with, caller must
+// cancel them first
+func (s *ServiceApp) SetConfig(cfg config.JiraConfigType) {
+	client := &http.Client{
+		Timeout: cfg.GetRequestTimeout(),
+	}
+
+	s.dataMutex.Lock()
+	defer s.dataMutex.Unlock()
+
+	s.config = cfg
+	s.client = client
+	s.tokens = newTokenSource(cfg, client)
+	s.accoundIds = resultMember{}
+	s.users = []userValues{}
+	s.projects = []string{}
+	s.selection = map[string]bool{}
+	s.filter = ""
+	s.myself = userValues{}
+	s.worklogs = WorklogData{}
+	s.summaryLog = SummaryLog{}
+	s.lastError = nil
+}
+
 // InitService implements ServiceType.
-func (s *ServiceApp) InitService() {
+func (s *ServiceApp) InitService(ctx context.Context) error {
 	s.handler.MoveCursor(termhandler.Position{2, 2})
 	s.handler.Draw("Loading... please kindly wait...")
 	s.handler.Render()
 
-	s.FetchMembers()
-	s.FetchUsers()
+	// authenticated user is preselected in users widget
+	if err := s.FetchMyself(ctx); err != nil {
+		return err
+	}
+
+	if err := s.FetchMembers(ctx); err != nil {
+		return err
+	}
+
+	if err := s.FetchUsers(ctx); err != nil {
+		return err
+	}
+
+	if err := s.LoadProjects(ctx); err != nil {
+		return err
+	}
+
+	return nil
 }
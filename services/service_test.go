@@ -0,0 +1,836 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+	"tui/config"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testAccountId  = "acc-1"
+	testOtherId    = "acc-2"
+	testIssueCount = 300
+)
+
+var (
+	testFrom = time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+	testTo   = time.Date(2024, time.March, 31, 0, 0, 0, 0, time.UTC)
+)
+
+// fakeJira serve search and issue worklog endpoints for testIssueCount
+// issues, even issues embed all their worklogs while odd issues need
+// FetchWorklogs to page through them
+func fakeJira(t *testing.T, failIssue string) *httptest.Server {
+	t.Helper()
+
+	worklogsOf := func(i int) []WorklogsWorklog {
+		count := 2
+		if i%2 == 1 {
+			count = 30
+		}
+
+		res := []WorklogsWorklog{}
+		for j := 0; j < count; j++ {
+			author := testAccountId
+			if j%2 == 1 {
+				author = testOtherId
+			}
+
+			res = append(res, WorklogsWorklog{
+				Author:           worklogAuthor{AccountId: author},
+				Comment:          WorklogComment(fmt.Sprintf("issue %d log %d", i, j)),
+				Started:          fmt.Sprintf("2024-03-%02dT%02d:00:00.000+0000", i%28+1, j%24),
+				TimeSpentSeconds: 3600,
+			})
+		}
+
+		return res
+	}
+
+	issuesOf := func(startAt int, maxResults int) []IssuesWorklog {
+		res := []IssuesWorklog{}
+		for i := startAt; i < startAt+maxResults && i < testIssueCount; i++ {
+			worklogs := worklogsOf(i)
+			embedded := worklogs
+			if len(embedded) > 20 {
+				embedded = embedded[:20]
+			}
+
+			res = append(res, IssuesWorklog{
+				Id: strconv.Itoa(i),
+				Fields: FieldIssue{
+					Worklog: WorklogField{Total: len(worklogs), Worklogs: embedded},
+				},
+			})
+		}
+
+		return res
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rest/api/2/search", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			StartAt    int `json:"startAt"`
+			MaxResults int `json:"maxResults"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode search body: %v", err)
+		}
+
+		json.NewEncoder(w).Encode(WorklogRes{
+			StartAt:    body.StartAt,
+			MaxResults: body.MaxResults,
+			Total:      testIssueCount,
+			Issues:     issuesOf(body.StartAt, body.MaxResults),
+		})
+	})
+	mux.HandleFunc("/rest/api/3/search/jql", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			NextPageToken string `json:"nextPageToken"`
+			MaxResults    int    `json:"maxResults"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode search body: %v", err)
+		}
+
+		startAt := 0
+		if body.NextPageToken != "" {
+			startAt, _ = strconv.Atoi(strings.TrimPrefix(body.NextPageToken, "token-"))
+		}
+
+		res := SearchJQLRes{Issues: issuesOf(startAt, body.MaxResults), IsLast: true}
+		if next := startAt + body.MaxResults; next < testIssueCount {
+			res.IsLast = false
+			res.NextPageToken = fmt.Sprintf("token-%d", next)
+		}
+
+		json.NewEncoder(w).Encode(res)
+	})
+	issueWorklogs := func(w http.ResponseWriter, r *http.Request) {
+		// path is /rest/api/{version}/issue/{id}/worklog
+		parts := strings.Split(r.URL.Path, "/")
+		id := parts[len(parts)-2]
+		if id == failIssue {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errorMessages":["Issue does not exist"]}`))
+			return
+		}
+
+		i, err := strconv.Atoi(id)
+		if err != nil {
+			t.Errorf("issue id %q: %v", id, err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		startAt, _ := strconv.Atoi(r.URL.Query().Get("startAt"))
+		worklogs := worklogsOf(i)
+		end := startAt + 10
+		if end > len(worklogs) {
+			end = len(worklogs)
+		}
+
+		json.NewEncoder(w).Encode(WorklogField{
+			StartAt:    startAt,
+			MaxResults: 10,
+			Total:      len(worklogs),
+			Worklogs:   worklogs[startAt:end],
+		})
+	}
+	mux.HandleFunc("/rest/api/2/issue/", issueWorklogs)
+	mux.HandleFunc("/rest/api/3/issue/", issueWorklogs)
+
+	return httptest.NewServer(mux)
+}
+
+func TestFetchIssues(t *testing.T) {
+	tcs := []struct {
+		name string
+		test func(t *testing.T)
+	}{
+		{
+			name: "aggregate every page of every issue",
+			test: func(t *testing.T) {
+				srv := fakeJira(t, "")
+				defer srv.Close()
+
+				svc := newTestService(t, srv.URL, 0)
+				svc.users = []userValues{{AccountId: testAccountId, DisplayName: "Andi"}}
+
+				err := svc.FetchIssues(context.Background(), FetchWorklogPayload{
+					AccountId: testAccountId,
+					From:      testFrom,
+					To:        testTo,
+				})
+				require.NoError(t, err)
+
+				// even issue has 1 own worklog, odd issue has 15
+				expectWorklog := testIssueCount/2*1 + testIssueCount/2*15
+				summary := svc.GetSummaryLog()
+				require.Equal(t, testIssueCount, summary.TotalBacklog)
+				require.Equal(t, expectWorklog, summary.TotalWorklog)
+				require.Equal(t, expectWorklog*3600, summary.TotalTimeSpent)
+
+				wl := svc.GetWorklogs()
+				require.Equal(t, 28, wl.LastDate)
+				require.Equal(t, "Andi", wl.Name)
+
+				totalLogs := 0
+				for _, day := range wl.Data {
+					for i := 1; i < len(day.Logs); i++ {
+						require.False(t, day.Logs[i].Started.Before(day.Logs[i-1].Started))
+					}
+					totalLogs += len(day.Logs)
+				}
+				require.Equal(t, expectWorklog, totalLogs)
+			},
+		},
+		{
+			name: "resolve user by account id when names collide",
+			test: func(t *testing.T) {
+				srv := fakeJira(t, "")
+				defer srv.Close()
+
+				svc := newTestService(t, srv.URL, 0)
+				svc.users = []userValues{
+					{AccountId: testOtherId, DisplayName: "Andi"},
+					{AccountId: testAccountId, DisplayName: "Andi"},
+				}
+
+				err := svc.FetchIssues(context.Background(), FetchWorklogPayload{
+					AccountId: testAccountId,
+					From:      testFrom,
+					To:        testTo,
+				})
+				require.NoError(t, err)
+
+				wl := svc.GetWorklogs()
+				require.Equal(t, testAccountId, wl.AccountId)
+				require.Equal(t, testAccountId, svc.GetUser().AccountId)
+				require.Equal(t, testIssueCount/2*1+testIssueCount/2*15, svc.GetSummaryLog().TotalWorklog)
+			},
+		},
+		{
+			name: "aggregate every page of v3 search",
+			test: func(t *testing.T) {
+				srv := fakeJira(t, "")
+				defer srv.Close()
+
+				svc := newTestService(t, srv.URL, 0)
+				svc.config.(*fakeConfig).searchAPI = config.SearchAPIV3
+				svc.users = []userValues{{AccountId: testAccountId, DisplayName: "Andi"}}
+
+				err := svc.FetchIssues(context.Background(), FetchWorklogPayload{
+					AccountId: testAccountId,
+					From:      testFrom,
+					To:        testTo,
+				})
+				require.NoError(t, err)
+
+				expectWorklog := testIssueCount/2*1 + testIssueCount/2*15
+				summary := svc.GetSummaryLog()
+				require.Equal(t, testIssueCount, summary.TotalBacklog)
+				require.Equal(t, expectWorklog, summary.TotalWorklog)
+			},
+		},
+		{
+			name: "keep previous data when an issue fails",
+			test: func(t *testing.T) {
+				srv := fakeJira(t, "151")
+				defer srv.Close()
+
+				svc := newTestService(t, srv.URL, 0)
+				svc.users = []userValues{{AccountId: testAccountId, DisplayName: "Andi"}}
+				svc.worklogs = WorklogData{Name: "previous"}
+
+				err := svc.FetchIssues(context.Background(), FetchWorklogPayload{
+					AccountId: testAccountId,
+					From:      testFrom,
+					To:        testTo,
+				})
+				require.ErrorIs(t, err, ErrNotFound)
+				require.Equal(t, "previous", svc.GetWorklogs().Name)
+				require.ErrorIs(t, svc.GetLastError(), ErrNotFound)
+			},
+		},
+		{
+			name: "cancelled fetch does not override data",
+			test: func(t *testing.T) {
+				srv := fakeJira(t, "")
+				defer srv.Close()
+
+				svc := newTestService(t, srv.URL, 0)
+				svc.users = []userValues{{AccountId: testAccountId, DisplayName: "Andi"}}
+				svc.worklogs = WorklogData{Name: "previous"}
+
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+
+				err := svc.FetchIssues(ctx, FetchWorklogPayload{AccountId: testAccountId, From: testFrom, To: testTo})
+				require.ErrorIs(t, err, context.Canceled)
+				require.Equal(t, "previous", svc.GetWorklogs().Name)
+			},
+		},
+		{
+			name: "reject filter escaping its parentheses",
+			test: func(t *testing.T) {
+				srv := fakeJira(t, "")
+				defer srv.Close()
+
+				svc := newTestService(t, srv.URL, 0)
+				svc.users = []userValues{{AccountId: testAccountId, DisplayName: "Andi"}}
+				svc.worklogs = WorklogData{Name: "previous"}
+
+				err := svc.FetchIssues(context.Background(), FetchWorklogPayload{
+					AccountId: testAccountId,
+					Filter:    "labels = a) OR (labels = b",
+					From:      testFrom,
+					To:        testTo,
+				})
+				require.ErrorIs(t, err, ErrInvalidJQL)
+				require.Equal(t, "previous", svc.GetWorklogs().Name)
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.test(t)
+		})
+	}
+}
+
+func TestMapWorklogData(t *testing.T) {
+	user := userValues{AccountId: testAccountId, TimeZone: "America/New_York"}
+	month := FetchWorklogPayload{From: testFrom, To: testTo}
+	payroll := FetchWorklogPayload{
+		From: time.Date(2024, time.February, 26, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2024, time.March, 25, 0, 0, 0, 0, time.UTC),
+	}
+	newWorklog := func(started string) WorklogsWorklog {
+		return WorklogsWorklog{
+			Id:               "10",
+			Author:           worklogAuthor{AccountId: testAccountId, TimeZone: "Asia/Jakarta"},
+			Started:          started,
+			TimeSpentSeconds: 1800,
+		}
+	}
+
+	tcs := []struct {
+		name          string
+		zone          string
+		param         FetchWorklogPayload
+		worklog       WorklogsWorklog
+		expectDay     int
+		expectSkipped int
+	}{
+		{
+			name:      "bucket by author zone",
+			zone:      config.WorklogZoneAuthor,
+			param:     month,
+			worklog:   newWorklog("2024-03-10T20:00:00.000+0000"),
+			expectDay: 11,
+		},
+		{
+			name:      "author zone move worklog out of month",
+			zone:      config.WorklogZoneAuthor,
+			param:     month,
+			worklog:   newWorklog("2024-03-31T20:00:00.000+0000"),
+			expectDay: 0,
+		},
+		{
+			name:      "bucket by viewed user zone",
+			zone:      config.WorklogZoneViewer,
+			param:     month,
+			worklog:   newWorklog("2024-03-10T02:00:00.000+0000"),
+			expectDay: 9,
+		},
+		{
+			name:      "viewed user zone move worklog into month",
+			zone:      config.WorklogZoneViewer,
+			param:     month,
+			worklog:   newWorklog("2024-04-01T03:00:00.000+0000"),
+			expectDay: 31,
+		},
+		{
+			name:      "bucket by utc",
+			zone:      config.WorklogZoneUTC,
+			param:     month,
+			worklog:   newWorklog("2024-03-31T20:00:00.000+0000"),
+			expectDay: 31,
+		},
+		{
+			name:      "reject same month of other year",
+			zone:      config.WorklogZoneUTC,
+			param:     month,
+			worklog:   newWorklog("2023-03-10T08:00:00.000+0000"),
+			expectDay: 0,
+		},
+		{
+			name:      "bucket by day of range across months",
+			zone:      config.WorklogZoneUTC,
+			param:     payroll,
+			worklog:   newWorklog("2024-03-01T08:00:00.000+0000"),
+			expectDay: 5,
+		},
+		{
+			name:      "last day of range in author zone",
+			zone:      config.WorklogZoneAuthor,
+			param:     payroll,
+			worklog:   newWorklog("2024-03-24T20:00:00.000+0000"),
+			expectDay: 29,
+		},
+		{
+			name:      "reject day after range",
+			zone:      config.WorklogZoneUTC,
+			param:     payroll,
+			worklog:   newWorklog("2024-03-26T08:00:00.000+0000"),
+			expectDay: 0,
+		},
+		{
+			name:          "skip unparsable started",
+			zone:          config.WorklogZoneUTC,
+			param:         month,
+			worklog:       newWorklog("10/03/2024 08:00"),
+			expectDay:     0,
+			expectSkipped: 1,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := newTestService(t, "", 0)
+			svc.config.(*fakeConfig).worklogZone = tc.zone
+
+			res := svc.mapWorklogData([]WorklogsWorklog{tc.worklog}, user, tc.param)
+			require.Equal(t, tc.expectSkipped, res.skipped)
+
+			if tc.expectDay == 0 {
+				require.Equal(t, 0, res.totalWorklog)
+				require.Empty(t, res.data)
+				return
+			}
+
+			require.Equal(t, 1, res.totalWorklog)
+			require.Equal(t, tc.expectDay, res.lastDate)
+			require.Equal(t, 1800, res.data[tc.expectDay].TimeSpent)
+		})
+	}
+}
+
+func TestFormatWorklogsDataSkipped(t *testing.T) {
+	newWorklog := func(started string) WorklogsWorklog {
+		return WorklogsWorklog{
+			Author:           worklogAuthor{AccountId: testAccountId},
+			Started:          started,
+			TimeSpentSeconds: 1800,
+		}
+	}
+
+	svc := newTestService(t, "", 0)
+	svc.config.(*fakeConfig).worklogZone = config.WorklogZoneUTC
+
+	issues := WorklogRes{Total: 2, Issues: []IssuesWorklog{
+		{Id: "1", Fields: FieldIssue{Worklog: WorklogField{Total: 2, Worklogs: []WorklogsWorklog{
+			newWorklog("2024-03-10T08:00:00.000+0000"),
+			newWorklog("not a date"),
+		}}}},
+		{Id: "2", Fields: FieldIssue{Worklog: WorklogField{Total: 1, Worklogs: []WorklogsWorklog{
+			newWorklog("2024-03-11T08:00:00.000+0000"),
+		}}}},
+	}}
+
+	err := svc.formatWorklogsData(
+		context.Background(),
+		issues,
+		userValues{AccountId: testAccountId},
+		FetchWorklogPayload{From: testFrom, To: testTo},
+	)
+	require.NoError(t, err)
+	require.Equal(t, SummaryLog{
+		TotalBacklog:   2,
+		TotalWorklog:   2,
+		TotalTimeSpent: 3600,
+		SkippedWorklog: 1,
+	}, svc.GetSummaryLog())
+	require.Equal(t, 11, svc.GetWorklogs().LastDate)
+}
+
+func TestSetConfig(t *testing.T) {
+	svc := newTestService(t, "https://old.atlassian.net", 0)
+	svc.users = []userValues{{AccountId: testAccountId, DisplayName: "Andi"}}
+	svc.worklogs = WorklogData{Name: "Andi"}
+	svc.summaryLog = SummaryLog{TotalWorklog: 3}
+
+	svc.SetConfig(&fakeConfig{url: "https://new.atlassian.net", maxRetries: 1})
+
+	require.Equal(t, "https://new.atlassian.net", svc.baseURL())
+	require.Empty(t, svc.GetUserItems())
+	require.Equal(t, WorklogData{}, svc.GetWorklogs())
+	require.Equal(t, SummaryLog{}, svc.GetSummaryLog())
+}
+
+func TestSetConfigDuringFetch(t *testing.T) {
+	srv := fakeJira(t, "")
+	defer srv.Close()
+
+	var newHits atomic.Int32
+	newSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		newHits.Add(1)
+	}))
+	defer newSrv.Close()
+
+	svc := newTestService(t, srv.URL, 0)
+	svc.users = []userValues{{AccountId: testAccountId, DisplayName: "Andi"}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- svc.FetchIssues(ctx, FetchWorklogPayload{AccountId: testAccountId, From: testFrom, To: testTo})
+	}()
+
+	cancel()
+	svc.SetConfig(&fakeConfig{url: newSrv.URL, searchAPI: config.SearchAPIV2, deployment: config.DeploymentCloud})
+
+	// fetch may finish before cancel, either way it never reach new profile
+	err := <-done
+	if err != nil {
+		require.ErrorIs(t, err, context.Canceled)
+	}
+	require.Zero(t, newHits.Load())
+}
+
+func TestFetchIssuesPaging(t *testing.T) {
+	tcs := []struct {
+		name        string
+		total       int
+		pageLimit   int // server cap of issues per page, 0 honour maxResults
+		expectCalls int32
+	}{
+		{name: "no issues", total: 0, expectCalls: 1},
+		{name: "single full page", total: searchPageSize, expectCalls: 1},
+		{name: "walk every page", total: 120, expectCalls: 3},
+		{name: "server cap page below maxResults", total: 45, pageLimit: 20, expectCalls: 3},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			var calls atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls.Add(1)
+				if r.URL.Path != "/rest/api/2/search" {
+					t.Errorf("unexpected path %s", r.URL.Path)
+				}
+
+				var body struct {
+					StartAt    int `json:"startAt"`
+					MaxResults int `json:"maxResults"`
+				}
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					t.Errorf("decode search body: %v", err)
+				}
+				if body.MaxResults != searchPageSize {
+					t.Errorf("expect maxResults %d, got %d", searchPageSize, body.MaxResults)
+				}
+
+				size := body.MaxResults
+				if tc.pageLimit > 0 {
+					size = tc.pageLimit
+				}
+
+				issues := []IssuesWorklog{}
+				for i := body.StartAt; i < body.StartAt+size && i < tc.total; i++ {
+					issues = append(issues, IssuesWorklog{Id: strconv.Itoa(i)})
+				}
+
+				json.NewEncoder(w).Encode(WorklogRes{
+					StartAt:    body.StartAt,
+					MaxResults: size,
+					Total:      tc.total,
+					Issues:     issues,
+				})
+			}))
+			defer srv.Close()
+
+			svc := newTestService(t, srv.URL, 0)
+			svc.users = []userValues{{AccountId: "acc-1", DisplayName: "Andi"}}
+
+			err := svc.FetchIssues(context.Background(), FetchWorklogPayload{AccountId: "acc-1", From: testFrom, To: testTo})
+			require.NoError(t, err)
+			require.Equal(t, tc.expectCalls, calls.Load())
+			require.Equal(t, tc.total, svc.GetSummaryLog().TotalBacklog)
+		})
+	}
+}
+
+func TestFetchMembers(t *testing.T) {
+	tcs := []struct {
+		name        string
+		pages       map[string]TeamMemberRes
+		expectCalls int32
+		expectIds   []string
+	}{
+		{
+			name: "single page",
+			pages: map[string]TeamMemberRes{
+				"": {Results: resultMember{{AccountId: "acc-1"}}},
+			},
+			expectCalls: 1,
+			expectIds:   []string{"acc-1"},
+		},
+		{
+			name: "follow cursor until last page",
+			pages: map[string]TeamMemberRes{
+				"": {
+					Results:  resultMember{{AccountId: "acc-1"}},
+					PageInfo: pageInfo{HasNextPage: true, EndCursor: "page-2"},
+				},
+				"page-2": {
+					Results:  resultMember{{AccountId: "acc-2"}},
+					PageInfo: pageInfo{HasNextPage: true, EndCursor: "page-3"},
+				},
+				"page-3": {Results: resultMember{{AccountId: "acc-3"}}},
+			},
+			expectCalls: 3,
+			expectIds:   []string{"acc-1", "acc-2", "acc-3"},
+		},
+		{
+			name: "send cursor with quote as json",
+			pages: map[string]TeamMemberRes{
+				"": {
+					Results:  resultMember{{AccountId: "acc-1"}},
+					PageInfo: pageInfo{HasNextPage: true, EndCursor: `page"2`},
+				},
+				`page"2`: {Results: resultMember{{AccountId: "acc-2"}}},
+			},
+			expectCalls: 2,
+			expectIds:   []string{"acc-1", "acc-2"},
+		},
+		{
+			name: "stop on empty cursor",
+			pages: map[string]TeamMemberRes{
+				"": {
+					Results:  resultMember{{AccountId: "acc-1"}},
+					PageInfo: pageInfo{HasNextPage: true},
+				},
+			},
+			expectCalls: 1,
+			expectIds:   []string{"acc-1"},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			var calls atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls.Add(1)
+				if r.URL.Path != "/gateway/api/public/teams/v1/org/org/teams/team/members" {
+					t.Errorf("unexpected path %s", r.URL.Path)
+				}
+
+				var body struct {
+					First int    `json:"first"`
+					After string `json:"after"`
+				}
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					t.Errorf("decode members body: %v", err)
+				}
+				if body.First != membersPageSize {
+					t.Errorf("expect first %d, got %d", membersPageSize, body.First)
+				}
+
+				json.NewEncoder(w).Encode(tc.pages[body.After])
+			}))
+			defer srv.Close()
+
+			svc := newTestService(t, srv.URL, 0)
+			require.NoError(t, svc.FetchMembers(context.Background()))
+
+			ids := []string{}
+			for _, member := range svc.accoundIds {
+				ids = append(ids, member.AccountId)
+			}
+			require.Equal(t, tc.expectCalls, calls.Load())
+			require.Equal(t, tc.expectIds, ids)
+		})
+	}
+}
+
+func TestFetchUsers(t *testing.T) {
+	tcs := []struct {
+		name        string
+		count       int
+		expectCalls int32
+	}{
+		{name: "no members", count: 0, expectCalls: 0},
+		{name: "single batch", count: usersBatchSize, expectCalls: 1},
+		{name: "split into batches", count: 120, expectCalls: 3},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			var calls atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls.Add(1)
+				if r.URL.Path != "/rest/api/2/user/bulk" {
+					t.Errorf("unexpected path %s", r.URL.Path)
+				}
+
+				query := r.URL.Query()
+				if query.Get("maxResults") != strconv.Itoa(usersBatchSize) {
+					t.Errorf("expect maxResults %d, got %s", usersBatchSize, query.Get("maxResults"))
+				}
+
+				ids := query["accountId"]
+				if len(ids) == 0 || len(ids) > usersBatchSize {
+					t.Errorf("expect 1 - %d account ids, got %d", usersBatchSize, len(ids))
+				}
+
+				users := []userValues{}
+				for _, id := range ids {
+					users = append(users, userValues{AccountId: id})
+				}
+				json.NewEncoder(w).Encode(UserRes{Values: users})
+			}))
+			defer srv.Close()
+
+			svc := newTestService(t, srv.URL, 0)
+			for i := 0; i < tc.count; i++ {
+				svc.accoundIds = append(svc.accoundIds, struct {
+					AccountId string `json:"accountId"`
+				}{AccountId: "acc-" + strconv.Itoa(i)})
+			}
+
+			require.NoError(t, svc.FetchUsers(context.Background()))
+			require.Equal(t, tc.expectCalls, calls.Load())
+			require.Len(t, svc.users, tc.count)
+
+			for i, user := range svc.users {
+				require.Equal(t, "acc-"+strconv.Itoa(i), user.AccountId)
+			}
+		})
+	}
+}
+
+func TestFetchWorklogs(t *testing.T) {
+	from := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC)
+
+	tcs := []struct {
+		name        string
+		total       int
+		expectCalls int32
+	}{
+		{name: "no worklogs", total: 0, expectCalls: 1},
+		{name: "single page", total: 10, expectCalls: 1},
+		{name: "follow startAt to last page", total: 25, expectCalls: 3},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			var calls atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls.Add(1)
+				if r.URL.Path != "/rest/api/2/issue/10/worklog" {
+					t.Errorf("unexpected path %s", r.URL.Path)
+				}
+
+				query := r.URL.Query()
+				if query.Get("startedAfter") != strconv.FormatInt(from.UnixMilli(), 10) {
+					t.Errorf("unexpected startedAfter %s", query.Get("startedAfter"))
+				}
+				if query.Get("startedBefore") != strconv.FormatInt(to.UnixMilli(), 10) {
+					t.Errorf("unexpected startedBefore %s", query.Get("startedBefore"))
+				}
+
+				// server cap page at 10 no matter maxResults
+				startAt, err := strconv.Atoi(query.Get("startAt"))
+				if err != nil {
+					t.Errorf("invalid startAt: %v", err)
+				}
+
+				worklogs := []WorklogsWorklog{}
+				for i := startAt; i < startAt+10 && i < tc.total; i++ {
+					worklogs = append(worklogs, WorklogsWorklog{Id: strconv.Itoa(i)})
+				}
+
+				json.NewEncoder(w).Encode(WorklogField{
+					StartAt:    startAt,
+					MaxResults: 10,
+					Total:      tc.total,
+					Worklogs:   worklogs,
+				})
+			}))
+			defer srv.Close()
+
+			svc := newTestService(t, srv.URL, 0)
+
+			res, err := svc.FetchWorklogs(context.Background(), "10", from, to)
+			require.NoError(t, err)
+			require.Equal(t, tc.expectCalls, calls.Load())
+			require.Equal(t, tc.total, res.Total)
+			require.Len(t, res.Worklogs, tc.total)
+
+			for i, worklog := range res.Worklogs {
+				require.Equal(t, strconv.Itoa(i), worklog.Id)
+			}
+		})
+	}
+}
+
+func TestMapWorklogDataAuthor(t *testing.T) {
+	newWorklog := func(accountId string) WorklogsWorklog {
+		return WorklogsWorklog{
+			Author:           worklogAuthor{AccountId: accountId},
+			Started:          "2024-03-10T08:00:00.000+0000",
+			TimeSpentSeconds: 1800,
+		}
+	}
+
+	tcs := []struct {
+		name        string
+		worklogs    []WorklogsWorklog
+		expectCount int
+	}{
+		{
+			name:        "count worklogs of selected user",
+			worklogs:    []WorklogsWorklog{newWorklog("acc-1"), newWorklog("acc-1")},
+			expectCount: 2,
+		},
+		{
+			name:        "skip worklogs of other authors",
+			worklogs:    []WorklogsWorklog{newWorklog("acc-1"), newWorklog("acc-2")},
+			expectCount: 1,
+		},
+		{
+			name:        "no worklogs of selected user",
+			worklogs:    []WorklogsWorklog{newWorklog("acc-2")},
+			expectCount: 0,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := newTestService(t, "", 0)
+
+			res := svc.mapWorklogData(
+				tc.worklogs,
+				userValues{AccountId: "acc-1"},
+				FetchWorklogPayload{From: testFrom, To: testTo},
+			)
+			require.Equal(t, tc.expectCount, res.totalWorklog)
+			require.Equal(t, tc.expectCount*1800, res.totalTimeSpent)
+			require.Equal(t, tc.expectCount*1800, res.data[10].TimeSpent)
+		})
+	}
+}
@@ -0,0 +1,231 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+type Project struct {
+	Id   string `json:"id"`
+	Key  string `json:"key"`
+	Name string `json:"name"`
+}
+
+type Team struct {
+	TeamId      string `json:"teamId"`
+	DisplayName string `json:"displayName"`
+}
+
+type teamsRes struct {
+	Entities []Team `json:"entities"`
+	Cursor   string `json:"cursor"`
+}
+
+type tenantContextsRes struct {
+	Data struct {
+		TenantContexts []struct {
+			OrgId   string `json:"orgId"`
+			CloudId string `json:"cloudId"`
+		} `json:"tenantContexts"`
+	} `json:"data"`
+}
+
+// FetchMyself implements ServiceType.
+// It check credentials against /myself and remember the authenticated user
+func (s *ServiceApp) FetchMyself(ctx context.Context) (err error) {
+	defer s.setLastError(ctx, &err)
+
+	resp, err := s.doRequest(ctx, http.MethodGet, fmt.Sprintf("%s/rest/api/2/myself", s.baseURL()), nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var myself userValues
+	if err := decodeJSON(resp, &myself); err != nil {
+		return err
+	}
+
+	s.dataMutex.Lock()
+	s.myself = myself
+	s.dataMutex.Unlock()
+	return nil
+}
+
+// GetMyself return user fetched by FetchMyself
+func (s *ServiceApp) GetMyself() userValues {
+	s.dataMutex.RLock()
+	defer s.dataMutex.RUnlock()
+
+	return s.myself
+}
+
+// FetchProjects list projects visible to the user
+func (s *ServiceApp) FetchProjects(ctx context.Context) ([]Project, error) {
+	resp, err := s.doRequest(ctx, http.MethodGet, fmt.Sprintf("%s/rest/api/2/project", s.baseURL()), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	projects := []Project{}
+	if err := decodeJSON(resp, &projects); err != nil {
+		return nil, err
+	}
+
+	return projects, nil
+}
+
+// LoadProjects implements ServiceType.
+// It fill project picker with configured projects, or every visible project
+// when none is configured. Configured projects start selected
+func (s *ServiceApp) LoadProjects(ctx context.Context) (err error) {
+	defer s.setLastError(ctx, &err)
+
+	keys := s.getConfig().GetProjects()
+	selected := map[string]bool{}
+	for _, key := range keys {
+		selected[key] = true
+	}
+
+	if len(keys) == 0 {
+		projects, err := s.FetchProjects(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, project := range projects {
+			keys = append(keys, project.Key)
+		}
+	}
+
+	s.dataMutex.Lock()
+	s.projects = keys
+	s.selection = selected
+	s.dataMutex.Unlock()
+	return nil
+}
+
+// GetProjects implements ServiceType.
+func (s *ServiceApp) GetProjects() []string {
+	s.dataMutex.RLock()
+	defer s.dataMutex.RUnlock()
+
+	return s.projects
+}
+
+// GetSelectedProjects implements ServiceType.
+// Empty result mean every visible project
+func (s *ServiceApp) GetSelectedProjects() []string {
+	s.dataMutex.RLock()
+	defer s.dataMutex.RUnlock()
+
+	res := []string{}
+	for _, key := range s.projects {
+		if s.selection[key] {
+			res = append(res, key)
+		}
+	}
+	return res
+}
+
+// ToggleProject implements ServiceType.
+func (s *ServiceApp) ToggleProject(key string) {
+	s.dataMutex.Lock()
+	defer s.dataMutex.Unlock()
+
+	s.selection[key] = !s.selection[key]
+}
+
+// FetchOrgID resolve organization of the site through tenantContexts
+// graphql query
+func (s *ServiceApp) FetchOrgID(ctx context.Context) (string, error) {
+	if _, _, tokens := s.session(); tokens != nil {
+		return "", ErrGatewayOAuth
+	}
+
+	baseURI := s.getConfig().GetAtlassianURL()
+
+	site, err := url.Parse(baseURI)
+	if err != nil {
+		return "", err
+	}
+
+	payload, err := json.Marshal(map[string]interface{}{
+		"query":     "query($hostNames: [String!]!) { tenantContexts(hostNames: $hostNames) { orgId cloudId } }",
+		"variables": map[string]interface{}{"hostNames": []string{site.Host}},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := s.doRequest(ctx, http.MethodPost, fmt.Sprintf("%s/gateway/api/graphql", baseURI), payload)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var resBody tenantContextsRes
+	if err := decodeJSON(resp, &resBody); err != nil {
+		return "", err
+	}
+
+	for _, tenant := range resBody.Data.TenantContexts {
+		if tenant.OrgId != "" {
+			// org may come as ari:cloud:platform::org/<id>
+			return tenant.OrgId[strings.LastIndex(tenant.OrgId, "/")+1:], nil
+		}
+	}
+
+	return "", errors.New("site has no organization")
+}
+
+// FetchTeams list teams of organization, following cursor until the last
+// page
+func (s *ServiceApp) FetchTeams(ctx context.Context, orgId string) ([]Team, error) {
+	if _, _, tokens := s.session(); tokens != nil {
+		return nil, ErrGatewayOAuth
+	}
+
+	baseURI := s.getConfig().GetAtlassianURL()
+	teams := []Team{}
+	cursor := ""
+
+	for {
+		urlTeams := fmt.Sprintf(
+			"%s/gateway/api/public/teams/v1/org/%s/teams?size=%d",
+			baseURI,
+			orgId,
+			membersPageSize,
+		)
+		if cursor != "" {
+			urlTeams = fmt.Sprintf("%s&cursor=%s", urlTeams, url.QueryEscape(cursor))
+		}
+
+		resp, err := s.doRequest(ctx, http.MethodGet, urlTeams, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var resBody teamsRes
+		err = decodeJSON(resp, &resBody)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		teams = append(teams, resBody.Entities...)
+
+		if resBody.Cursor == "" || len(resBody.Entities) == 0 {
+			break
+		}
+		cursor = resBody.Cursor
+	}
+
+	return teams, nil
+}
@@ -0,0 +1,208 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"tui/auth"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetupLookups(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rest/api/2/myself", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"accountId":"acc-1","displayName":"Andi"}`))
+	})
+	mux.HandleFunc("/gateway/api/graphql", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":{"tenantContexts":[{"orgId":"ari:cloud:platform::org/org-1","cloudId":"cloud-1"}]}}`))
+	})
+	mux.HandleFunc("/gateway/api/public/teams/v1/org/org-1/teams", func(w http.ResponseWriter, r *http.Request) {
+		res := teamsRes{Entities: []Team{{TeamId: "team-1", DisplayName: "Platform"}}, Cursor: "next"}
+		if r.URL.Query().Get("cursor") == "next" {
+			res = teamsRes{Entities: []Team{{TeamId: "team-2", DisplayName: "Mobile"}}}
+		}
+		json.NewEncoder(w).Encode(res)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	svc := newTestService(t, srv.URL, 0)
+	ctx := context.Background()
+
+	require.NoError(t, svc.FetchMyself(ctx))
+	require.Equal(t, "Andi", svc.GetMyself().DisplayName)
+
+	orgId, err := svc.FetchOrgID(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "org-1", orgId)
+
+	teams, err := svc.FetchTeams(ctx, orgId)
+	require.NoError(t, err)
+	require.Equal(t, []Team{
+		{TeamId: "team-1", DisplayName: "Platform"},
+		{TeamId: "team-2", DisplayName: "Mobile"},
+	}, teams)
+}
+
+func TestGatewayOAuth(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request to %s", r.URL.Path)
+	}))
+	defer srv.Close()
+
+	tcs := []struct {
+		name      string
+		userList  []string
+		test      func(svc *ServiceApp) error
+		expect    resultMember
+		expectErr error
+	}{
+		{
+			name:      "team roster",
+			test:      func(svc *ServiceApp) error { return svc.FetchMembers(context.Background()) },
+			expectErr: ErrGatewayOAuth,
+		},
+		{
+			name:     "account id roster",
+			userList: []string{"acc-1", "acc-2"},
+			test:     func(svc *ServiceApp) error { return svc.FetchMembers(context.Background()) },
+			expect:   resultMember{{AccountId: "acc-1"}, {AccountId: "acc-2"}},
+		},
+		{
+			name: "organization lookup",
+			test: func(svc *ServiceApp) error {
+				_, err := svc.FetchOrgID(context.Background())
+				return err
+			},
+			expectErr: ErrGatewayOAuth,
+		},
+		{
+			name: "team list",
+			test: func(svc *ServiceApp) error {
+				_, err := svc.FetchTeams(context.Background(), "org-1")
+				return err
+			},
+			expectErr: ErrGatewayOAuth,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := newTestService(t, srv.URL, 0)
+			svc.config.(*fakeConfig).userList = tc.userList
+			svc.tokens = auth.NewTokenSource(auth.OAuthConfig{}, srv.Client(), auth.NewFileStore(filepath.Join(t.TempDir(), "token.json")))
+
+			err := tc.test(svc)
+			if tc.expectErr != nil {
+				require.ErrorIs(t, err, tc.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expect, svc.accoundIds)
+		})
+	}
+}
+
+func TestGetDefaultAccountId(t *testing.T) {
+	tcs := []struct {
+		name   string
+		myself userValues
+		users  []userValues
+		expect string
+	}{
+		{
+			name:   "authenticated user on roster",
+			myself: userValues{AccountId: "acc-2", DisplayName: "Budi"},
+			users:  []userValues{{AccountId: "acc-1", DisplayName: "Andi"}, {AccountId: "acc-2", DisplayName: "Budi"}},
+			expect: "acc-2",
+		},
+		{
+			name:   "authenticated user outside roster",
+			myself: userValues{AccountId: "acc-3", DisplayName: "Citra"},
+			users:  []userValues{{AccountId: "acc-1", DisplayName: "Andi"}},
+			expect: "acc-1",
+		},
+		{
+			name:   "data center user matched by name",
+			myself: userValues{Name: "budi", DisplayName: "Budi S"},
+			users:  []userValues{{Name: "andi", DisplayName: "Andi"}, {Name: "budi", DisplayName: "Budi S"}},
+			expect: "budi",
+		},
+		{
+			name:   "empty roster",
+			myself: userValues{AccountId: "acc-1", DisplayName: "Andi"},
+			users:  []userValues{},
+			expect: "",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := newTestService(t, "", 0)
+			svc.myself = tc.myself
+			svc.users = tc.users
+
+			require.Equal(t, tc.expect, svc.GetDefaultAccountId())
+		})
+	}
+}
+
+func TestLoadProjects(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/rest/api/2/project", r.URL.Path)
+		w.Write([]byte(`[{"id":"1","key":"TUI","name":"Tui"},{"id":"2","key":"OPS","name":"Ops"}]`))
+	}))
+	defer srv.Close()
+
+	tcs := []struct {
+		name           string
+		configured     []string
+		toggle         []string
+		expectProjects []string
+		expectSelected []string
+	}{
+		{
+			name:           "configured projects start selected",
+			configured:     []string{"TUI", "OPS"},
+			expectProjects: []string{"TUI", "OPS"},
+			expectSelected: []string{"TUI", "OPS"},
+		},
+		{
+			name:           "unselect configured project",
+			configured:     []string{"TUI", "OPS"},
+			toggle:         []string{"TUI"},
+			expectProjects: []string{"TUI", "OPS"},
+			expectSelected: []string{"OPS"},
+		},
+		{
+			name:           "visible projects start unselected",
+			expectProjects: []string{"TUI", "OPS"},
+			expectSelected: []string{},
+		},
+		{
+			name:           "toggle twice restore scope",
+			toggle:         []string{"OPS", "TUI", "OPS"},
+			expectProjects: []string{"TUI", "OPS"},
+			expectSelected: []string{"TUI"},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := newTestService(t, srv.URL, 0)
+			svc.config.(*fakeConfig).projects = tc.configured
+
+			require.NoError(t, svc.LoadProjects(context.Background()))
+			for _, key := range tc.toggle {
+				svc.ToggleProject(key)
+			}
+
+			require.Equal(t, tc.expectProjects, svc.GetProjects())
+			require.Equal(t, tc.expectSelected, svc.GetSelectedProjects())
+		})
+	}
+}
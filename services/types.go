@@ -1,6 +1,11 @@
 package services
 
-import "time"
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+	"tui/utils"
+)
 
 // members
 type pageInfo struct {
@@ -8,10 +13,18 @@ type pageInfo struct {
 	EndCursor   string `json:"endCursor"`
 }
 
-type resultMember []struct {
+// teamMemberReq ask for page of team members after cursor
+type teamMemberReq struct {
+	First int    `json:"first"`
+	After string `json:"after,omitempty"`
+}
+
+type member struct {
 	AccountId string `json:"accountId"`
 }
 
+type resultMember []member
+
 type TeamMemberRes struct {
 	PageInfo pageInfo     `json:"pageInfo"`
 	Results  resultMember `json:"results"`
@@ -21,6 +34,8 @@ type TeamMemberRes struct {
 type userValues struct {
 	Self        string `json:"self"`
 	AccountId   string `json:"accountId"`
+	Name        string `json:"name"` // data center username
+	Key         string `json:"key"`
 	AccountType string `json:"accountType"`
 	DisplayName string `json:"displayName"`
 	EmailAdrres string `json:"emailAddress"`
@@ -28,6 +43,23 @@ type userValues struct {
 	TimeZone    string `json:"timeZone"`
 }
 
+// id identify user in jql and worklog author, data center has no accountId
+// so username is used instead
+func (u userValues) id() string {
+	if u.AccountId != "" {
+		return u.AccountId
+	}
+
+	return u.Name
+}
+
+// UserItem is roster entry for users widget, AccountId hold username on
+// data center
+type UserItem struct {
+	AccountId   string
+	DisplayName string
+}
+
 type UserRes struct {
 	Self       string       `json:"self"`
 	MaxResults int          `json:"maxResults"`
@@ -39,16 +71,65 @@ type UserRes struct {
 
 // worklogs
 
+type worklogAuthor struct {
+	Self         string `json:"self"`
+	AccountId    string `json:"accountId"`
+	Name         string `json:"name"` // data center username
+	Key          string `json:"key"`
+	DisplayName  string `json:"displayName"`
+	EmailAddress string `json:"emailAddress"`
+	TimeZone     string `json:"timeZone"`
+}
+
+// id match userValues.id
+func (a worklogAuthor) id() string {
+	if a.AccountId != "" {
+		return a.AccountId
+	}
+
+	return a.Name
+}
+
+// WorklogComment is plain text of worklog comment, one line per line to
+// show. v2 api return it as string whose single newlines are folded while v3
+// return Atlassian Document Format which get rendered
+type WorklogComment string
+
+func (c *WorklogComment) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		*c = ""
+		return nil
+	}
+
+	if data[0] == '"' {
+		var str string
+		if err := json.Unmarshal(data, &str); err != nil {
+			return err
+		}
+		*c = WorklogComment(utils.FoldCommentLines(str))
+		return nil
+	}
+
+	text, err := utils.RenderADF(data)
+	if err != nil {
+		return err
+	}
+	*c = WorklogComment(text)
+	return nil
+}
+
 type WorklogsWorklog struct {
-	Self             string `json:"self"`
-	Comment          string `json:"comment"`
-	Created          string `json:"created"`
-	Updated          string `json:"updated"`
-	Started          string `json:"started"`
-	TimeSpent        string `json:"timeSpent"`
-	TimeSpentSeconds int    `json:"timeSpentSeconds"`
-	Id               string `json:"id"`
-	IssueId          string `json:"issueId"`
+	Self             string         `json:"self"`
+	Author           worklogAuthor  `json:"author"`
+	Comment          WorklogComment `json:"comment"`
+	Created          string         `json:"created"`
+	Updated          string         `json:"updated"`
+	Started          string         `json:"started"`
+	TimeSpent        string         `json:"timeSpent"`
+	TimeSpentSeconds int            `json:"timeSpentSeconds"`
+	Id               string         `json:"id"`
+	IssueId          string         `json:"issueId"`
 }
 
 type WorklogField struct {
@@ -78,13 +159,28 @@ type WorklogRes struct {
 	Issues     []IssuesWorklog `json:"issues"`
 }
 
+type SearchJQLRes struct {
+	Issues        []IssuesWorklog `json:"issues"`
+	NextPageToken string          `json:"nextPageToken"`
+	IsLast        bool            `json:"isLast"`
+}
+
 type Logs struct {
 	TimeRange string
 	Comment   string
-  Started   time.Time
+	Started   time.Time
 }
 
 type FormattedWorklogData struct {
 	TimeSpent int
 	Logs      []Logs
 }
+
+// per issue aggregation, built by single worker in formatWorklogsData
+type issueWorklogs struct {
+	data           map[int]FormattedWorklogData
+	lastDate       int
+	totalWorklog   int
+	totalTimeSpent int
+	skipped        int // worklogs whose started can't be parsed
+}
@@ -0,0 +1,178 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ADFNode is single node of Atlassian Document Format document
+type ADFNode struct {
+	Type    string                 `json:"type"`
+	Text    string                 `json:"text"`
+	Attrs   map[string]interface{} `json:"attrs"`
+	Marks   []ADFMark              `json:"marks"`
+	Content []ADFNode              `json:"content"`
+}
+
+type ADFMark struct {
+	Type  string                 `json:"type"`
+	Attrs map[string]interface{} `json:"attrs"`
+}
+
+// RenderADF convert ADF json document into plain text. Blocks are separated
+// by blank line, list items and quote lines are put on their own line
+func RenderADF(raw []byte) (string, error) {
+	var doc ADFNode
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(renderADFBlock(doc, "")), nil
+}
+
+func renderADFBlock(node ADFNode, indent string) string {
+	switch node.Type {
+	case "doc", "blockquote", "panel", "expand", "nestedExpand", "layoutSection", "layoutColumn":
+		childIndent := indent
+		if node.Type == "blockquote" {
+			childIndent = ""
+		}
+
+		blocks := []string{}
+		for _, child := range node.Content {
+			if block := renderADFBlock(child, childIndent); block != "" {
+				blocks = append(blocks, block)
+			}
+		}
+
+		if node.Type == "blockquote" {
+			return prefixLines(strings.Join(blocks, "\n"), indent+"> ")
+		}
+		return strings.Join(blocks, "\n\n")
+	case "paragraph", "heading":
+		return indent + renderADFInline(node.Content)
+	case "bulletList", "orderedList":
+		start := 1
+		if order, ok := node.Attrs["order"].(float64); ok {
+			start = int(order)
+		}
+
+		items := []string{}
+		for i, item := range node.Content {
+			bullet := "- "
+			if node.Type == "orderedList" {
+				bullet = fmt.Sprintf("%d. ", start+i)
+			}
+			items = append(items, renderADFListItem(item, indent, bullet))
+		}
+		return strings.Join(items, "\n")
+	case "codeBlock":
+		return prefixLines(renderADFInline(node.Content), indent)
+	case "rule":
+		return indent + "---"
+	case "mediaSingle", "mediaGroup", "media":
+		return ""
+	}
+
+	// unknown block, keep whatever inline text it has
+	return indent + renderADFInline(node.Content)
+}
+
+func renderADFListItem(item ADFNode, indent string, bullet string) string {
+	lines := []string{}
+	for i, child := range item.Content {
+		if child.Type == "bulletList" || child.Type == "orderedList" {
+			lines = append(lines, renderADFBlock(child, indent+"  "))
+			continue
+		}
+
+		text := renderADFBlock(child, "")
+		if i == 0 {
+			text = indent + bullet + text
+		} else {
+			text = indent + "  " + text
+		}
+		lines = append(lines, text)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func renderADFInline(nodes []ADFNode) string {
+	var sb strings.Builder
+
+	for _, node := range nodes {
+		switch node.Type {
+		case "text":
+			sb.WriteString(renderADFText(node))
+		case "hardBreak":
+			sb.WriteString("\n")
+		case "mention":
+			name := attrString(node.Attrs, "text")
+			if name == "" {
+				name = attrString(node.Attrs, "id")
+			}
+			if !strings.HasPrefix(name, "@") {
+				name = "@" + name
+			}
+			sb.WriteString(name)
+		case "emoji":
+			emoji := attrString(node.Attrs, "text")
+			if emoji == "" {
+				emoji = attrString(node.Attrs, "shortName")
+			}
+			sb.WriteString(emoji)
+		case "inlineCard", "blockCard", "embedCard":
+			sb.WriteString(attrString(node.Attrs, "url"))
+		case "status":
+			sb.WriteString(fmt.Sprintf("[%s]", attrString(node.Attrs, "text")))
+		case "date":
+			sb.WriteString(attrString(node.Attrs, "timestamp"))
+		default:
+			sb.WriteString(renderADFInline(node.Content))
+		}
+	}
+
+	return sb.String()
+}
+
+func renderADFText(node ADFNode) string {
+	text := node.Text
+
+	for _, mark := range node.Marks {
+		switch mark.Type {
+		case "code":
+			text = fmt.Sprintf("`%s`", text)
+		case "link":
+			href := attrString(mark.Attrs, "href")
+			if href != "" && href != text {
+				text = fmt.Sprintf("%s (%s)", text, href)
+			}
+		}
+	}
+
+	return text
+}
+
+func attrString(attrs map[string]interface{}, key string) string {
+	val, ok := attrs[key]
+	if !ok || val == nil {
+		return ""
+	}
+
+	if str, ok := val.(string); ok {
+		return str
+	}
+
+	return fmt.Sprintf("%v", val)
+}
+
+func prefixLines(text string, prefix string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = prefix + line
+	}
+
+	return strings.Join(lines, "\n")
+}
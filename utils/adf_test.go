@@ -0,0 +1,118 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderADF(t *testing.T) {
+	tcs := []struct {
+		name   string
+		doc    string
+		expect string
+	}{
+		{
+			name: "paragraphs",
+			doc: `{"type":"doc","version":1,"content":[
+        {"type":"paragraph","content":[{"type":"text","text":"first"}]},
+        {"type":"paragraph","content":[{"type":"text","text":"second"}]}
+      ]}`,
+			expect: "first\n\nsecond",
+		},
+		{
+			name: "mention, emoji, link and code",
+			doc: `{"type":"doc","version":1,"content":[
+        {"type":"paragraph","content":[
+          {"type":"text","text":"pair with "},
+          {"type":"mention","attrs":{"id":"123","text":"@Andi"}},
+          {"type":"text","text":" "},
+          {"type":"emoji","attrs":{"shortName":":smile:","text":"😄"}},
+          {"type":"text","text":" fix "},
+          {"type":"text","text":"nil map","marks":[{"type":"code"}]},
+          {"type":"text","text":" see "},
+          {"type":"text","text":"PR","marks":[{"type":"link","attrs":{"href":"https://example.com/pr/1"}}]}
+        ]}
+      ]}`,
+			expect: "pair with @Andi 😄 fix `nil map` see PR (https://example.com/pr/1)",
+		},
+		{
+			name: "nested lists",
+			doc: `{"type":"doc","version":1,"content":[
+        {"type":"bulletList","content":[
+          {"type":"listItem","content":[
+            {"type":"paragraph","content":[{"type":"text","text":"review"}]},
+            {"type":"orderedList","attrs":{"order":3},"content":[
+              {"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"api"}]}]},
+              {"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"tui"}]}]}
+            ]}
+          ]},
+          {"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"deploy"}]}]}
+        ]}
+      ]}`,
+			expect: "- review\n  3. api\n  4. tui\n- deploy",
+		},
+		{
+			name: "code block and hard break",
+			doc: `{"type":"doc","version":1,"content":[
+        {"type":"paragraph","content":[{"type":"text","text":"line one"},{"type":"hardBreak"},{"type":"text","text":"line two"}]},
+        {"type":"codeBlock","attrs":{"language":"go"},"content":[{"type":"text","text":"go test ./..."}]}
+      ]}`,
+			expect: "line one\nline two\n\ngo test ./...",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := RenderADF([]byte(tc.doc))
+			require.NoError(t, err)
+			require.Equal(t, tc.expect, res)
+		})
+	}
+}
+
+func TestFormatCommentLines(t *testing.T) {
+	tcs := []struct {
+		name   string
+		text   func(t *testing.T) string
+		expect []string
+	}{
+		{
+			name: "adf hard break and code block",
+			text: func(t *testing.T) string {
+				text, err := RenderADF([]byte(`{"type":"doc","content":[
+					{"type":"paragraph","content":[
+						{"type":"text","text":"deployed"},
+						{"type":"hardBreak"},
+						{"type":"text","text":"rolled back"}
+					]},
+					{"type":"codeBlock","content":[{"type":"text","text":"make build\nmake deploy"}]}
+				]}`))
+				require.NoError(t, err)
+				return text
+			},
+			expect: []string{"deployed", "rolled back", "make build", "make deploy"},
+		},
+		{
+			name: "folded plain text",
+			text: func(t *testing.T) string {
+				return FoldCommentLines("standup\n\n- review api\n- deploy\nto staging")
+			},
+			expect: []string{"standup", "- review api", "- deploy to staging"},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expect, FormatCommentLines(tc.text(t), 60))
+		})
+	}
+}
+
+func TestFormatCommentDescLineBreaks(t *testing.T) {
+	text := "standup\n\n- review api\n- deploy\nto staging"
+	expect := []string{"standup", "- review api", "- deploy to staging"}
+
+	res := FormatCommentDesc(text, 60)
+	require.Equal(t, expect, res)
+}
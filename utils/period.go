@@ -0,0 +1,136 @@
+package utils
+
+import (
+	"fmt"
+	"time"
+)
+
+// kind of period, month is built in and always available
+const (
+	PeriodMonth  = "month"  // calendar month
+	PeriodCutoff = "cutoff" // payroll month closing on CutoffDay
+	PeriodFiscal = "fiscal" // fiscal month of Weeks pattern, like 4-4-5
+)
+
+// Period is named way of slicing calendar into consecutive ranges
+type Period struct {
+	Name      string
+	Kind      string
+	CutoffDay int       // cutoff: last day of every period, 1 - 28
+	Weeks     []int     // fiscal: weeks of every month of quarter
+	YearStart time.Time // fiscal: first day of any fiscal year
+}
+
+// PeriodRange is single occurrence of period, From and To are inclusive
+type PeriodRange struct {
+	Name     string
+	YearName string
+	From     time.Time
+	To       time.Time
+}
+
+// Label name occurrence as a whole, empty for free range
+func (r PeriodRange) Label() string {
+	if r.Name == "" {
+		return ""
+	}
+
+	return fmt.Sprintf("%s %s", r.Name, r.YearName)
+}
+
+// At return occurrence of period containing t
+func (p Period) At(t time.Time) PeriodRange {
+	date := TruncateDate(t)
+
+	switch p.Kind {
+	case PeriodCutoff:
+		return p.cutoffAt(date)
+	case PeriodFiscal:
+		return p.fiscalAt(date)
+	}
+
+	from, to := MonthRange(int(date.Month()), date.Year())
+	return PeriodRange{
+		Name:     date.Month().String(),
+		YearName: fmt.Sprintf("%d", date.Year()),
+		From:     from,
+		To:       to,
+	}
+}
+
+// Shift return occurrence n periods after r, negative n go backward
+func (p Period) Shift(r PeriodRange, n int) PeriodRange {
+	for ; n > 0; n-- {
+		r = p.At(r.To.AddDate(0, 0, 1))
+	}
+
+	for ; n < 0; n++ {
+		r = p.At(r.From.AddDate(0, 0, -1))
+	}
+
+	return r
+}
+
+// PerYear return number of occurrences in a year
+func (p Period) PerYear() int {
+	if p.Kind == PeriodFiscal {
+		return len(p.Weeks) * 4
+	}
+
+	return 12
+}
+
+// cutoffAt name period after month it close in, so period closing on
+// Mar 25 is Feb26-Mar25 2024
+func (p Period) cutoffAt(date time.Time) PeriodRange {
+	end := time.Date(date.Year(), date.Month(), p.CutoffDay, 0, 0, 0, 0, time.UTC)
+	if date.After(end) {
+		end = end.AddDate(0, 1, 0)
+	}
+	from := end.AddDate(0, -1, 1)
+
+	return PeriodRange{
+		Name:     fmt.Sprintf("%s-%s", from.Format("Jan02"), end.Format("Jan02")),
+		YearName: fmt.Sprintf("%d", end.Year()),
+		From:     from,
+		To:       end,
+	}
+}
+
+// fiscalAt treat every fiscal year as four quarters of Weeks pattern, so
+// 4-4-5 year is 52 weeks. Fiscal year is named after year it start in
+func (p Period) fiscalAt(date time.Time) PeriodRange {
+	quarterDays := 0
+	for _, weeks := range p.Weeks {
+		quarterDays += weeks * 7
+	}
+	yearDays := quarterDays * 4
+
+	start := TruncateDate(p.YearStart)
+	days := int(date.Sub(start).Hours() / 24)
+	years := days / yearDays
+	if days < 0 && days%yearDays != 0 {
+		years--
+	}
+
+	from := start.AddDate(0, 0, years*yearDays)
+	for i := 0; ; i++ {
+		to := from.AddDate(0, 0, p.Weeks[i%len(p.Weeks)]*7-1)
+		if !date.After(to) {
+			yearStart := start.AddDate(0, 0, years*yearDays)
+			return PeriodRange{
+				Name:     fmt.Sprintf("P%02d", i+1),
+				YearName: fmt.Sprintf("FY%d", yearStart.Year()),
+				From:     from,
+				To:       to,
+			}
+		}
+		from = to.AddDate(0, 0, 1)
+	}
+}
+
+// GetPeriodWorkDays return target of occurrence r and target until today in
+// seconds
+func GetPeriodWorkDays(r PeriodRange, hoursPerDay int) (int, int) {
+	return GetRangeWorkDays(r.From, r.To, hoursPerDay)
+}
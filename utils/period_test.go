@@ -0,0 +1,128 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeriodAt(t *testing.T) {
+	payroll := Period{Name: "payroll", Kind: PeriodCutoff, CutoffDay: 25}
+	fiscal := Period{
+		Name:      "fiscal",
+		Kind:      PeriodFiscal,
+		Weeks:     []int{4, 4, 5},
+		YearStart: time.Date(2023, time.December, 31, 0, 0, 0, 0, time.UTC),
+	}
+
+	tcs := []struct {
+		name        string
+		period      Period
+		at          string
+		expectLabel string
+		expectFrom  string
+		expectTo    string
+	}{
+		{
+			name:        "calendar month",
+			period:      Period{Name: "month", Kind: PeriodMonth},
+			at:          "2024-02-10",
+			expectLabel: "February 2024",
+			expectFrom:  "2024-02-01",
+			expectTo:    "2024-02-29",
+		},
+		{
+			name:        "cutoff day close period",
+			period:      payroll,
+			at:          "2024-03-25",
+			expectLabel: "Feb26-Mar25 2024",
+			expectFrom:  "2024-02-26",
+			expectTo:    "2024-03-25",
+		},
+		{
+			name:        "day after cutoff open next period",
+			period:      payroll,
+			at:          "2024-12-26",
+			expectLabel: "Dec26-Jan25 2025",
+			expectFrom:  "2024-12-26",
+			expectTo:    "2025-01-25",
+		},
+		{
+			name:        "first fiscal month",
+			period:      fiscal,
+			at:          "2024-01-27",
+			expectLabel: "P01 FY2023",
+			expectFrom:  "2023-12-31",
+			expectTo:    "2024-01-27",
+		},
+		{
+			name:        "five week fiscal month",
+			period:      fiscal,
+			at:          "2024-03-01",
+			expectLabel: "P03 FY2023",
+			expectFrom:  "2024-02-25",
+			expectTo:    "2024-03-30",
+		},
+		{
+			name:        "fiscal year before start",
+			period:      fiscal,
+			at:          "2023-12-30",
+			expectLabel: "P12 FY2023",
+			expectFrom:  "2023-11-26",
+			expectTo:    "2023-12-30",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			at, err := time.Parse(time.DateOnly, tc.at)
+			require.NoError(t, err)
+
+			res := tc.period.At(at)
+			require.Equal(t, tc.expectLabel, res.Label())
+			require.Equal(t, tc.expectFrom, res.From.Format(time.DateOnly))
+			require.Equal(t, tc.expectTo, res.To.Format(time.DateOnly))
+		})
+	}
+}
+
+func TestPeriodShift(t *testing.T) {
+	tcs := []struct {
+		name       string
+		period     Period
+		steps      int
+		expectFrom string
+		expectTo   string
+	}{
+		{
+			name:       "month forward across year",
+			period:     Period{Kind: PeriodMonth},
+			steps:      11,
+			expectFrom: "2025-02-01",
+			expectTo:   "2025-02-28",
+		},
+		{
+			name:       "cutoff backward",
+			period:     Period{Kind: PeriodCutoff, CutoffDay: 25},
+			steps:      -2,
+			expectFrom: "2023-12-26",
+			expectTo:   "2024-01-25",
+		},
+		{
+			name:       "fiscal one year forward",
+			period:     Period{Kind: PeriodFiscal, Weeks: []int{4, 4, 5}, YearStart: time.Date(2023, time.December, 31, 0, 0, 0, 0, time.UTC)},
+			steps:      12,
+			expectFrom: "2025-02-23",
+			expectTo:   "2025-03-29",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			res := tc.period.Shift(tc.period.At(time.Date(2024, time.March, 10, 0, 0, 0, 0, time.UTC)), tc.steps)
+			require.Equal(t, tc.expectFrom, res.From.Format(time.DateOnly))
+			require.Equal(t, tc.expectTo, res.To.Format(time.DateOnly))
+		})
+	}
+}
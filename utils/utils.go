@@ -3,11 +3,17 @@ package utils
 import (
 	"fmt"
 	"log"
+	"sort"
 	"strings"
 	"time"
 )
 
-var WORKING_HOURS = 8
+// WORKING_HOURS is default length of working day, profiles may set their own
+const WORKING_HOURS = 8
+
+// GRID_DAYS is number of days worklog grid hold, five weeks. Ranges and
+// periods can't be longer than that
+const GRID_DAYS = 35
 
 func StrToPtr(str string) *string {
 	return &str
@@ -104,57 +110,140 @@ func FormatSecondToHourMinute(seconds int, isLongFormat bool) string {
 	return sMinutes
 }
 
+// FormatCommentDesc wrap comment into lines of n characters, capped at 4
+// lines. Single newlines are folded into space like the original, blank
+// lines and lines starting with list or quote marker begin a new line
 func FormatCommentDesc(s string, n int) []string {
-	normalizedT := strings.ReplaceAll(s, "\n", " ")
-	normalizedT = strings.Join(strings.Fields(normalizedT), " ")
-	if len(normalizedT) > n*4 {
-		normalizedT = normalizedT[0 : n*4]
+	return wrapCommentLines(splitCommentParagraphs(s), n)
+}
+
+// FormatCommentLines wrap comment like FormatCommentDesc but every newline
+// begin a new line, for comments whose line breaks are all meant like ADF
+func FormatCommentLines(s string, n int) []string {
+	lines := []string{}
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.Join(strings.Fields(line), " "); line != "" {
+			lines = append(lines, line)
+		}
 	}
 
-	if n < 0 || len(normalizedT) < n {
-		return []string{normalizedT}
+	return wrapCommentLines(lines, n)
+}
+
+// FoldCommentLines fold single newlines of plain text comment into space,
+// leaving one line per paragraph, list item or quote line
+func FoldCommentLines(s string) string {
+	return strings.Join(splitCommentParagraphs(s), "\n")
+}
+
+func wrapCommentLines(paragraphs []string, n int) []string {
+	if n <= 0 {
+		return []string{strings.Join(paragraphs, " ")}
 	}
 
 	var result []string
-	for i := 0; i < len(normalizedT); i += n {
-		end := i + n
-		if end > len(normalizedT) {
-			end = len(normalizedT)
+	for _, paragraph := range paragraphs {
+		runes := []rune(paragraph)
+		for i := 0; i < len(runes); i += n {
+			if len(result) == 4 {
+				return result
+			}
+
+			end := i + n
+			if end > len(runes) {
+				end = len(runes)
+			}
+
+			result = append(result, string(runes[i:end]))
 		}
+	}
 
-		result = append(result, normalizedT[i:end])
+	if len(result) == 0 {
+		return []string{""}
 	}
 	return result
 }
 
-func GetWorkDays(month int, year int) (int, int) {
-	tMonth := 0
-	tToday := 0
+func splitCommentParagraphs(s string) []string {
+	paragraphs := []string{}
+	current := []string{}
+
+	flush := func() {
+		if len(current) > 0 {
+			paragraphs = append(paragraphs, strings.Join(current, " "))
+			current = []string{}
+		}
+	}
+
+	for _, line := range strings.Split(s, "\n") {
+		trimmed := strings.Join(strings.Fields(line), " ")
+		if trimmed == "" {
+			flush()
+			continue
+		}
+
+		if isCommentMarkerLine(trimmed) {
+			flush()
+		}
+		current = append(current, trimmed)
+	}
+	flush()
+
+	return paragraphs
+}
 
-	if month <= 0 || year <= 0 {
-		log.Printf("error month or/and year is not valid number")
-		return tMonth, tToday
+func isCommentMarkerLine(line string) bool {
+	for _, prefix := range []string{"- ", "* ", "• ", "> ", "---"} {
+		if strings.HasPrefix(line, prefix) {
+			return true
+		}
 	}
 
-	now := time.Now()
-	startDate, lastDate := CalculateRangeDateInMonth(month, year)
-	parsedStartDate, _ := time.Parse("2006-01-02", startDate)
-	parsedLastDate, _ := time.Parse("2006-01-02", lastDate)
+	// ordered list item like "12. "
+	digits := 0
+	for digits < len(line) && line[digits] >= '0' && line[digits] <= '9' {
+		digits++
+	}
 
-	weekDayCountWhole := getWeekdays(parsedStartDate, parsedLastDate)
-	if month < int(now.Month()) && year <= now.Year() { // given month is behind of current month
-		tMonth = weekDayCountWhole * WORKING_HOURS * 60 * 60
-		tToday = tMonth
-	} else if month == int(now.Month()) && year == now.Year() { // given date is same with current date
-		weekDayCountUntilN := getWeekdays(time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location()), now)
+	return digits > 0 && strings.HasPrefix(line[digits:], ". ")
+}
+
+// GetRangeWorkDays return target of whole range and target until today in
+// seconds of hoursPerDay working days, both ends are inclusive. Range in the
+// past is fully due while range in the future has nothing due yet
+func GetRangeWorkDays(from time.Time, to time.Time, hoursPerDay int) (int, int) {
+	tRange := 0
+	tToday := 0
 
-		tMonth = weekDayCountWhole * WORKING_HOURS * 60 * 60
-		tToday = weekDayCountUntilN * WORKING_HOURS * 60 * 60
-	} else { // given month is after the current month (or future)
-		tMonth = weekDayCountWhole * WORKING_HOURS * 60 * 60
+	if from.IsZero() || to.IsZero() || TruncateDate(from).After(TruncateDate(to)) {
+		log.Printf("error range is not valid")
+		return tRange, tToday
 	}
 
-	return tMonth, tToday
+	from = TruncateDate(from)
+	to = TruncateDate(to)
+	today := TruncateDate(time.Now())
+
+	tRange = getWeekdays(from, to) * hoursPerDay * 60 * 60
+	switch {
+	case to.Before(today): // given range is behind today
+		tToday = tRange
+	case !from.After(today): // given range contain today
+		tToday = getWeekdays(from, today) * hoursPerDay * 60 * 60
+	}
+
+	return tRange, tToday
+}
+
+// MonthRange return first and last day of month as UTC dates
+func MonthRange(month int, year int) (time.Time, time.Time) {
+	from := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+	return from, from.AddDate(0, 1, -1)
+}
+
+// TruncateDate drop clock and zone of t, keeping its calendar date as UTC
+func TruncateDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
 }
 
 func getWeekdays(startDate, endDate time.Time) int {
@@ -182,10 +271,18 @@ func getlastDateOfMonth(month int, year int) int {
 	return firstOfNextMonth.AddDate(0, 0, -1).Day()
 }
 
+// ValidateENVs report first empty value, checked in key order so the same
+// key is reported every run
 func ValidateENVs(envs map[string]string) error {
-	for key, val := range envs {
-		if val == "" {
-			return fmt.Errorf("error validating: %s", key)
+	keys := make([]string, 0, len(envs))
+	for key := range envs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if envs[key] == "" {
+			return fmt.Errorf("error validating: %s is empty", key)
 		}
 	}
 
@@ -251,74 +251,75 @@ func TestValidateENVs(t *testing.T) {
 	}
 }
 
-func TestGetWorkDays(t *testing.T) {
+func TestGetRangeWorkDays(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+
 	tcs := []struct {
-		name string
-		test func(t *testing.T)
+		name        string
+		from        time.Time
+		to          time.Time
+		expectRange int
+		expectToday int
 	}{
 		{
-			name: "default behaviour",
-			test: func(t *testing.T) {
-				month := 1
-				year := 2024
-
-				expectTargetMonth := 662400
-				expectTargetToday := 662400
-
-				targetMonth, targetToday := GetWorkDays(month, year)
-				require.Equal(t, expectTargetMonth, targetMonth)
-				require.Equal(t, expectTargetToday, targetToday)
-			},
+			name:        "payroll period in the past",
+			from:        date(2024, time.February, 26),
+			to:          date(2024, time.March, 25),
+			expectRange: 21 * 8 * 3600,
+			expectToday: 21 * 8 * 3600,
 		},
 		{
-			name: "error formatting month year int not valid",
-			test: func(t *testing.T) {
-				month := -1
-				year := 2024
-
-				expectTargetMonth := 0
-				expectTargetToday := 0
-
-				targetMonth, targetToday := GetWorkDays(month, year)
-				require.Equal(t, expectTargetMonth, targetMonth)
-				require.Equal(t, expectTargetToday, targetToday)
-			},
+			name:        "two weeks in the future",
+			from:        date(2999, time.January, 7),
+			to:          date(2999, time.January, 20),
+			expectRange: 10 * 8 * 3600,
+			expectToday: 0,
 		},
 		{
-			name: "get equal current month and year",
-			test: func(t *testing.T) {
-				now := time.Now()
-				month := int(now.Month())
-				year := now.Year()
-
-				expectTargetMonth := 604800
-				expectTargetToday := 288000
-
-				targetMonth, targetToday := GetWorkDays(month, year)
-				require.Equal(t, expectTargetMonth, targetMonth)
-				require.Equal(t, expectTargetToday, targetToday)
-			},
+			name:        "clock and zone are ignored",
+			from:        time.Date(2024, time.March, 1, 23, 0, 0, 0, time.FixedZone("WIB", 7*3600)),
+			to:          time.Date(2024, time.March, 1, 1, 0, 0, 0, time.FixedZone("WIB", 7*3600)),
+			expectRange: 8 * 3600,
+			expectToday: 8 * 3600,
 		},
 		{
-			name: "get more then current month and year",
-			test: func(t *testing.T) {
-				nowAfter := time.Now().AddDate(1, 1, 0)
-				month := int(nowAfter.Month())
-				year := nowAfter.Year()
+			name: "from after to",
+			from: date(2024, time.March, 25),
+			to:   date(2024, time.March, 1),
+		},
+		{
+			name: "zero range",
+		},
+	}
 
-				expectTargetMonth := 662400
-				expectTargetToday := 0
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			targetRange, targetToday := GetRangeWorkDays(tc.from, tc.to, WORKING_HOURS)
+			require.Equal(t, tc.expectRange, targetRange)
+			require.Equal(t, tc.expectToday, targetToday)
+		})
+	}
+}
 
-				targetMonth, targetToday := GetWorkDays(month, year)
-				require.Equal(t, expectTargetMonth, targetMonth)
-				require.Equal(t, expectTargetToday, targetToday)
-			},
-		},
+func TestMonthRange(t *testing.T) {
+	tcs := []struct {
+		name       string
+		month      int
+		year       int
+		expectFrom string
+		expectTo   string
+	}{
+		{name: "leap february", month: 2, year: 2024, expectFrom: "2024-02-01", expectTo: "2024-02-29"},
+		{name: "december", month: 12, year: 2020, expectFrom: "2020-12-01", expectTo: "2020-12-31"},
 	}
 
 	for _, tc := range tcs {
 		t.Run(tc.name, func(t *testing.T) {
-			tc.test(t)
+			from, to := MonthRange(tc.month, tc.year)
+			require.Equal(t, tc.expectFrom, from.Format(time.DateOnly))
+			require.Equal(t, tc.expectTo, to.Format(time.DateOnly))
 		})
 	}
 }